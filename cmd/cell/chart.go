@@ -0,0 +1,322 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"cell/chart"
+	"cell/phonedb"
+)
+
+// runChart implements "cell chart", which draws a chart of the phones in the
+// terminal or as an SVG file.
+func runChart(args []string) int {
+	flags := newFlagSet("chart", "cell chart [-data file] [-where expr] [-o file.svg] [-width n] [-bins n] [-top n] <chart> [field]",
+		"Chart draws one of these charts with Unicode blocks, or as a standalone\n"+
+			"SVG file with -o:\n\n"+
+			"  launches          phones announced each year\n"+
+			"  oems              phones made by the OEMs with the most phones\n"+
+			"  share             share of the phones announced each year made by the\n"+
+			"                    OEMs with the most phones\n"+
+			"  histogram <field> phones by value of a numeric field, such as weight,\n"+
+			"                    size or ppi\n"+
+			"  trend <field>     average of a numeric field by announcement year\n"+
+			"  scatter <field>   a numeric field against announcement year, one point\n"+
+			"                    per phone; only drawn as SVG\n\n"+
+			"Text charts are as wide as the terminal unless -width says otherwise.")
+	data := dataFlag(flags)
+	where := whereFlag(flags)
+	output := flags.String("o", "", "write the chart as SVG to `file` instead of drawing it as text")
+	width := flags.Int("width", 0, "width of a text chart in `columns`; 0 fits the terminal")
+	bins := flags.Int("bins", 20, "largest `number` of bins of a histogram")
+	top := flags.Int("top", 10, "`number` of OEMs to chart; the rest are added up as \"Others\"")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	name, field, ok := chartArgs(flags)
+	if !ok {
+		flags.Usage()
+		return 2
+	}
+	if name == "scatter" && *output == "" {
+		fmt.Fprintln(os.Stderr, "cell: a scatter plot is only drawn as SVG; use -o")
+		return 2
+	}
+	var metric phonedb.Metric
+	if field != "" {
+		var err error
+		if metric, err = phonedb.FieldMetric(field); err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 2
+		}
+	}
+
+	cells, err := loadCatalog(*data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	cells = cells.Where(where)
+
+	c := newChart(cells, name, field, metric, *bins, *top)
+	if *output != "" {
+		err = writeOutput(*output, c.writeSVG)
+	} else {
+		columns := *width
+		if columns <= 0 {
+			columns = terminalWidth()
+		}
+		fmt.Println(c.labels.Title + ":")
+		err = c.drawText(columns)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	return 0
+}
+
+// chartData is a chart of the phones. Every chart is drawn from bars, lines
+// or points.
+type chartData struct {
+	name   string
+	labels chart.Labels
+	bars   []chart.Bar
+	series []chart.Series
+	points []chart.Point
+}
+
+// newChart builds the named chart of cells, as listed by "cell help chart".
+// metric is the metric of field, for the charts that draw one; bins and top
+// are the -bins and -top flags.
+func newChart(cells *phonedb.Catalog, name, field string, metric phonedb.Metric, bins, top int) chartData {
+	c := chartData{name: name}
+	switch name {
+	case "launches":
+		byYear := cells.CountPhonesByYear()
+		c.bars = yearBars(byYear.Value.Counts)
+		c.labels = chart.Labels{
+			Title: fmt.Sprintf("Phones announced each year (%d without a year excluded)", byYear.Excluded),
+			X:     "Year announced",
+			Y:     "Phones",
+		}
+	case "oems":
+		c.bars = oemBars(cells.CountPhonesByOEM(), top)
+		c.labels = chart.Labels{Title: "Phones by OEM", X: "OEM", Y: "Phones"}
+	case "share":
+		share := cells.OEMShareByYear()
+		c.series = shareSeries(share.Value, cells.CountPhonesByOEM(), top)
+		c.labels = chart.Labels{
+			Title: fmt.Sprintf("Share of the phones announced each year by OEM (%d without a year excluded)", share.Excluded),
+			X:     "Year announced",
+			Y:     "Share of phones (%)",
+		}
+	case "histogram":
+		histogram := cells.Histogram(metric, bins)
+		for _, bin := range histogram.Value {
+			label := chart.FormatValue(bin.Low) + " to " + chart.FormatValue(bin.High)
+			c.bars = append(c.bars, chart.Bar{Label: label, Value: float64(bin.Count)})
+		}
+		c.labels = chart.Labels{
+			Title: fmt.Sprintf("Phones by %s (%d without one excluded)", field, histogram.Excluded),
+			X:     field,
+			Y:     "Phones",
+		}
+	case "trend":
+		means := phonedb.GroupBy(cells, phonedb.ByYear, phonedb.Mean(metric))
+		line := chart.Series{Name: "Average " + field}
+		for _, year := range phonedb.SortedKeys(means.Value) {
+			line.Points = append(line.Points, chart.Point{X: float64(year), Y: means.Value[year]})
+		}
+		c.series = []chart.Series{line}
+		c.labels = chart.Labels{
+			Title: fmt.Sprintf("Average %s by announcement year (%d without both excluded)", field, means.Excluded),
+			X:     "Year announced",
+			Y:     "Average " + field,
+		}
+	case "scatter":
+		excluded := 0
+		for _, cell := range cells.Phones() {
+			year, hasYear := phonedb.ByYear(cell)
+			value, hasValue := metric(cell)
+			if !hasYear || !hasValue {
+				excluded++
+				continue
+			}
+			c.points = append(c.points, chart.Point{X: float64(year), Y: value})
+		}
+		c.labels = chart.Labels{
+			Title: fmt.Sprintf("%s by announcement year (%d without both excluded)", field, excluded),
+			X:     "Year announced",
+			Y:     field,
+		}
+	}
+	return c
+}
+
+// writeSVG writes the chart to w as a standalone SVG document.
+func (c chartData) writeSVG(w io.Writer) error {
+	switch c.name {
+	case "scatter":
+		return chart.WriteScatterSVG(w, c.labels, c.points)
+	case "share", "trend":
+		return chart.WriteLinesSVG(w, c.labels, c.series)
+	}
+	return chart.WriteBarsSVG(w, c.labels, c.bars)
+}
+
+// chartArgs returns the chart named by the arguments of "cell chart" and
+// the field it draws, if it needs one.
+func chartArgs(flags *flag.FlagSet) (name, field string, ok bool) {
+	switch name = flags.Arg(0); name {
+	case "launches", "oems", "share":
+		return name, "", flags.NArg() == 1
+	case "histogram", "trend", "scatter":
+		return name, flags.Arg(1), flags.NArg() == 2
+	}
+	return "", "", false
+}
+
+// drawText draws the chart to standard output as text, columns wide. A
+// single line is drawn as one bar per point, several lines as one sparkline
+// each. The launches and trend charts end with a sparkline of their values.
+func (c chartData) drawText(columns int) error {
+	bars := c.bars
+	if len(c.series) == 1 {
+		for _, p := range c.series[0].Points {
+			bars = append(bars, chart.Bar{Label: chart.FormatValue(p.X), Value: p.Y})
+		}
+	}
+	if len(c.series) > 1 {
+		return writeSparklines(os.Stdout, c.series)
+	}
+	if err := chart.WriteBars(os.Stdout, bars, columns); err != nil {
+		return err
+	}
+	if (c.name == "launches" || c.name == "trend") && len(bars) > 1 {
+		values := make([]float64, len(bars))
+		for i, bar := range bars {
+			values[i] = bar.Value
+		}
+		_, err := fmt.Printf("Trend %s to %s: %s\n", bars[0].Label, bars[len(bars)-1].Label, chart.Sparkline(values))
+		return err
+	}
+	return nil
+}
+
+// writeSparklines writes one line per series to w with its name, a
+// sparkline of its values and its last value.
+func writeSparklines(w io.Writer, series []chart.Series) error {
+	nameWidth := 0
+	for _, line := range series {
+		if len(line.Name) > nameWidth {
+			nameWidth = len(line.Name)
+		}
+	}
+	for _, line := range series {
+		values := make([]float64, len(line.Points))
+		for i, p := range line.Points {
+			values[i] = p.Y
+		}
+		last := "-"
+		if n := len(line.Points); n > 0 {
+			last = chart.FormatValue(line.Points[n-1].Y) + " in " + chart.FormatValue(line.Points[n-1].X)
+		}
+		if _, err := fmt.Fprintf(w, "%-*s %s %s\n", nameWidth, line.Name, chart.Sparkline(values), last); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// yearBars returns one bar per year from the first year in counts to the
+// last, including the years without phones, so that gaps show.
+func yearBars(counts map[uint]int) []chart.Bar {
+	years := phonedb.SortedKeys(counts)
+	if len(years) == 0 {
+		return nil
+	}
+	var bars []chart.Bar
+	for year := years[0]; year <= years[len(years)-1]; year++ {
+		bars = append(bars, chart.Bar{Label: strconv.FormatUint(uint64(year), 10), Value: float64(counts[year])})
+	}
+	return bars
+}
+
+// topOEMs returns the top OEMs with the most phones, ties broken by name,
+// and the rest.
+func topOEMs(counts map[string]int, top int) (leaders, rest []string) {
+	oems := phonedb.SortedKeys(counts)
+	sort.SliceStable(oems, func(i, j int) bool { return counts[oems[i]] > counts[oems[j]] })
+	if top < 0 {
+		top = 0
+	}
+	if top > len(oems) {
+		top = len(oems)
+	}
+	return oems[:top], oems[top:]
+}
+
+// oemBars returns one bar per OEM for the top OEMs with the most phones,
+// followed by an "Others" bar adding up the rest.
+func oemBars(counts map[string]int, top int) []chart.Bar {
+	leaders, rest := topOEMs(counts, top)
+	var bars []chart.Bar
+	for _, oem := range leaders {
+		bars = append(bars, chart.Bar{Label: oem, Value: float64(counts[oem])})
+	}
+	others := 0
+	for _, oem := range rest {
+		others += counts[oem]
+	}
+	if others > 0 {
+		bars = append(bars, chart.Bar{Label: "Others", Value: float64(others)})
+	}
+	return bars
+}
+
+// shareSeries returns one line per OEM for the top OEMs with the most
+// phones, followed by an "Others" line adding up the rest, with the share of
+// every year from the first year in shares to the last in percent.
+func shareSeries(shares map[uint]map[string]float64, counts map[string]int, top int) []chart.Series {
+	years := phonedb.SortedKeys(shares)
+	if len(years) == 0 {
+		return nil
+	}
+	leaders, rest := topOEMs(counts, top)
+	names := append([]string{}, leaders...)
+	if len(rest) > 0 {
+		names = append(names, "Others")
+	}
+
+	series := make([]chart.Series, len(names))
+	for i, name := range names {
+		series[i].Name = name
+		for year := years[0]; year <= years[len(years)-1]; year++ {
+			share := shares[year][name]
+			if name == "Others" {
+				for _, oem := range rest {
+					share += shares[year][oem]
+				}
+			}
+			series[i].Points = append(series[i].Points, chart.Point{X: float64(year), Y: 100 * share})
+		}
+	}
+	return series
+}
+
+// terminalWidth returns the number of columns of the terminal standard
+// output writes to, falling back to $COLUMNS and then to 80.
+func terminalWidth() int {
+	if columns := terminalColumns(os.Stdout); columns > 0 {
+		return columns
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return 80
+}
@@ -0,0 +1,285 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+)
+
+// Labels are the title of a chart and the names of its axes.
+type Labels struct {
+	Title string
+	X, Y  string
+}
+
+// Point is a point of a line chart or a scatter plot.
+type Point struct {
+	X, Y float64
+}
+
+// Series is a named line of a line chart.
+type Series struct {
+	Name   string
+	Points []Point
+}
+
+// The size of an SVG chart and of the margins around its plot area, which
+// hold the title, the ticks and the names of the axes, in pixels.
+const (
+	svgWidth     = 800
+	svgHeight    = 450
+	marginTop    = 50
+	marginRight  = 30
+	marginBottom = 70
+	marginLeft   = 70
+	// extra margin to the right of a chart with several series, for the
+	// legend
+	legendWidth = 150
+)
+
+// palette are the colors of the series of a chart, in order. A series
+// named "Others" is drawn in grey.
+var palette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// WriteBarsSVG writes bars to w as a standalone SVG column chart, one
+// column per bar from left to right. The value axis starts at zero.
+func WriteBarsSVG(w io.Writer, labels Labels, bars []Bar) error {
+	values := make([]float64, len(bars))
+	for i, bar := range bars {
+		values[i] = bar.Value
+	}
+	s := newSVG(labels, false)
+	s.y = newAxis(values, true)
+	s.yAxis()
+
+	band := (s.right - s.left) / math.Max(float64(len(bars)), 1)
+	// label every bar if the labels fit, and otherwise tilt them and label
+	// only as many as fit
+	longest := 0
+	for _, bar := range bars {
+		if n := len([]rune(bar.Label)); n > longest {
+			longest = n
+		}
+	}
+	tilt := float64(longest)*7 > band
+	every := 1
+	if tilt {
+		every = int(math.Ceil(14 / band))
+	}
+	for i, bar := range bars {
+		x := s.left + float64(i)*band
+		y := s.py(math.Max(bar.Value, 0))
+		s.printf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %s</title></rect>`+"\n",
+			x+band*0.1, y, band*0.8, s.bottom-y, palette[0], escape(bar.Label), FormatValue(bar.Value))
+		if i%every != 0 {
+			continue
+		}
+		center := x + band/2
+		if tilt {
+			s.printf(`<text x="%.1f" y="%.1f" text-anchor="end" transform="rotate(-45 %.1f %.1f)">%s</text>`+"\n",
+				center, s.bottom+14, center, s.bottom+14, escape(bar.Label))
+		} else {
+			s.printf(`<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", center, s.bottom+18, escape(bar.Label))
+		}
+	}
+	s.axisLines()
+	return s.writeTo(w)
+}
+
+// WriteLinesSVG writes series to w as a standalone SVG line chart, with a
+// legend if there are several series. Points are joined in the order given.
+func WriteLinesSVG(w io.Writer, labels Labels, series []Series) error {
+	var xs, ys []float64
+	for _, line := range series {
+		for _, p := range line.Points {
+			xs = append(xs, p.X)
+			ys = append(ys, p.Y)
+		}
+	}
+	s := newSVG(labels, len(series) > 1)
+	s.x, s.y = newAxis(xs, false), newAxis(ys, true)
+	s.xAxis()
+	s.yAxis()
+
+	for i, line := range series {
+		color := seriesColor(i, line.Name)
+		points := make([]string, len(line.Points))
+		for j, p := range line.Points {
+			points[j] = fmt.Sprintf("%.1f,%.1f", s.px(p.X), s.py(p.Y))
+		}
+		s.printf(`<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", strings.Join(points, " "), color)
+		for _, p := range line.Points {
+			s.printf(`<circle cx="%.1f" cy="%.1f" r="2.5" fill="%s"><title>%s %s: %s</title></circle>`+"\n",
+				s.px(p.X), s.py(p.Y), color, escape(line.Name), FormatValue(p.X), FormatValue(p.Y))
+		}
+		if len(series) > 1 {
+			y := s.top + float64(i)*20
+			s.printf(`<rect x="%.1f" y="%.1f" width="12" height="12" fill="%s"/>`+"\n", s.right+20, y, color)
+			s.printf(`<text x="%.1f" y="%.1f">%s</text>`+"\n", s.right+38, y+10, escape(line.Name))
+		}
+	}
+	s.axisLines()
+	return s.writeTo(w)
+}
+
+// WriteScatterSVG writes points to w as a standalone SVG scatter plot. The
+// points are translucent, so that points drawn on top of each other show as
+// darker spots.
+func WriteScatterSVG(w io.Writer, labels Labels, points []Point) error {
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i], ys[i] = p.X, p.Y
+	}
+	s := newSVG(labels, false)
+	s.x, s.y = newAxis(xs, false), newAxis(ys, false)
+	s.xAxis()
+	s.yAxis()
+	for _, p := range points {
+		s.printf(`<circle cx="%.1f" cy="%.1f" r="3" fill="%s" fill-opacity="0.35"/>`+"\n", s.px(p.X), s.py(p.Y), palette[0])
+	}
+	s.axisLines()
+	return s.writeTo(w)
+}
+
+// seriesColor returns the color of the i-th series of a chart.
+func seriesColor(i int, name string) string {
+	if name == "Others" {
+		return "#999999"
+	}
+	return palette[i%len(palette)]
+}
+
+// escape escapes the text of an SVG element or attribute.
+func escape(s string) string {
+	return html.EscapeString(s)
+}
+
+// axis maps a range of values, which starts and ends on a tick, to a side
+// of the plot area.
+type axis struct {
+	lo, hi float64
+	ticks  []float64
+}
+
+// newAxis returns an axis covering values with about five round ticks,
+// such as 0, 50, 100, 150 and 200. If zero is set the axis starts at zero
+// or below.
+func newAxis(values []float64, zero bool) axis {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if len(values) == 0 {
+		lo, hi = 0, 1
+	}
+	if zero {
+		lo = math.Min(lo, 0)
+	}
+	if hi <= lo {
+		hi = lo + 1
+	}
+
+	step, exp := roundStep((hi - lo) / 5)
+	// k steps are k*step/10^-exp rather than k*step*10^exp, which keeps
+	// ticks such as 0.6 free of rounding errors
+	tick := func(k float64) float64 {
+		if exp < 0 {
+			return k * step / math.Pow10(-exp)
+		}
+		return k * step * math.Pow10(exp)
+	}
+	first, last := math.Floor(lo/tick(1)), math.Ceil(hi/tick(1))
+	a := axis{lo: tick(first), hi: tick(last)}
+	for k := first; k <= last; k++ {
+		a.ticks = append(a.ticks, tick(k))
+	}
+	return a
+}
+
+// roundStep returns the smallest of 1, 2, 2.5 and 5 times a power of ten
+// that is at least step, as the multiple and the exponent.
+func roundStep(step float64) (float64, int) {
+	exp := int(math.Floor(math.Log10(step)))
+	for _, m := range []float64{1, 2, 2.5, 5} {
+		if m*math.Pow10(exp) >= step {
+			return m, exp
+		}
+	}
+	return 1, exp + 1
+}
+
+// svg builds an SVG chart: a title, a plot area and its axes.
+type svg struct {
+	strings.Builder
+	// edges of the plot area in pixels
+	left, top, right, bottom float64
+	x, y                     axis
+}
+
+// newSVG starts a chart with the given labels, leaving room for a legend if
+// legend is set.
+func newSVG(labels Labels, legend bool) *svg {
+	s := &svg{left: marginLeft, top: marginTop, right: svgWidth - marginRight, bottom: svgHeight - marginBottom}
+	if legend {
+		s.right -= legendWidth
+	}
+	s.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		svgWidth, svgHeight, svgWidth, svgHeight)
+	s.printf(`<rect width="%d" height="%d" fill="white"/>`+"\n", svgWidth, svgHeight)
+	s.printf(`<text x="%d" y="28" text-anchor="middle" font-size="16">%s</text>`+"\n", svgWidth/2, escape(labels.Title))
+	s.printf(`<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n", (s.left+s.right)/2, svgHeight-12, escape(labels.X))
+	s.printf(`<text x="18" y="%.1f" text-anchor="middle" transform="rotate(-90 18 %.1f)">%s</text>`+"\n",
+		(s.top+s.bottom)/2, (s.top+s.bottom)/2, escape(labels.Y))
+	return s
+}
+
+// printf appends to the chart.
+func (s *svg) printf(format string, args ...any) {
+	fmt.Fprintf(s, format, args...)
+}
+
+// px returns the horizontal position of the value x.
+func (s *svg) px(x float64) float64 {
+	return s.left + (x-s.x.lo)/(s.x.hi-s.x.lo)*(s.right-s.left)
+}
+
+// py returns the vertical position of the value y.
+func (s *svg) py(y float64) float64 {
+	return s.bottom - (y-s.y.lo)/(s.y.hi-s.y.lo)*(s.bottom-s.top)
+}
+
+// xAxis draws the ticks of the horizontal axis.
+func (s *svg) xAxis() {
+	for _, t := range s.x.ticks {
+		x := s.px(t)
+		s.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#000000"/>`+"\n", x, s.bottom, x, s.bottom+5)
+		s.printf(`<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", x, s.bottom+18, FormatValue(t))
+	}
+}
+
+// yAxis draws the ticks of the vertical axis, with grid lines across the
+// plot area.
+func (s *svg) yAxis() {
+	for _, t := range s.y.ticks {
+		y := s.py(t)
+		s.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#e0e0e0"/>`+"\n", s.left, y, s.right, y)
+		s.printf(`<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`+"\n", s.left-8, y+4, FormatValue(t))
+	}
+}
+
+// axisLines draws the lines of both axes on top of the plot and closes the
+// document.
+func (s *svg) axisLines() {
+	s.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#000000"/>`+"\n", s.left, s.top, s.left, s.bottom)
+	s.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#000000"/>`+"\n", s.left, s.bottom, s.right, s.bottom)
+	s.printf("</svg>\n")
+}
+
+// writeTo writes the chart to w.
+func (s *svg) writeTo(w io.Writer) error {
+	_, err := io.WriteString(w, s.String())
+	return err
+}
@@ -0,0 +1,89 @@
+package chart
+
+import (
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewAxis(t *testing.T) {
+	tests := []struct {
+		values []float64
+		zero   bool
+		want   axis
+	}{
+		{nil, false, axis{0, 1, []float64{0, 0.2, 0.4, 0.6, 0.8, 1}}},
+		{[]float64{41, 730}, true, axis{0, 800, []float64{0, 200, 400, 600, 800}}},
+		{[]float64{1996, 2020}, false, axis{1995, 2020, []float64{1995, 2000, 2005, 2010, 2015, 2020}}},
+		{[]float64{3, 3}, false, axis{3, 4, []float64{3, 3.2, 3.4, 3.6, 3.8, 4}}},
+		{[]float64{-0.05, 0.3}, false, axis{-0.1, 0.3, []float64{-0.1, 0, 0.1, 0.2, 0.3}}},
+	}
+	for _, tt := range tests {
+		if got := newAxis(tt.values, tt.zero); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("newAxis(%v, %v) = %+v; want %+v", tt.values, tt.zero, got, tt.want)
+		}
+	}
+}
+
+// elements counts the elements of an SVG document by name, failing the test
+// if it is not well-formed XML.
+func elements(t *testing.T, doc string) map[string]int {
+	t.Helper()
+	counts := make(map[string]int)
+	decoder := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return counts
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, doc)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			counts[start.Name.Local]++
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	labels := Labels{Title: "Phones by OEM <& friends>", X: "OEM", Y: "Phones"}
+
+	var bars strings.Builder
+	if err := WriteBarsSVG(&bars, labels, []Bar{{"AT&T", 3}, {"LG", 5}}); err != nil {
+		t.Fatal(err)
+	}
+	// the background and one rectangle per bar
+	if got := elements(t, bars.String())["rect"]; got != 3 {
+		t.Errorf("WriteBarsSVG() drew %d rectangles; want 3", got)
+	}
+	if !strings.Contains(bars.String(), "Phones by OEM &lt;&amp; friends&gt;") {
+		t.Errorf("WriteBarsSVG() did not escape the title:\n%s", bars.String())
+	}
+
+	var lines strings.Builder
+	series := []Series{
+		{"Samsung", []Point{{2019, 40}, {2020, 35}}},
+		{"Others", []Point{{2019, 60}, {2020, 65}}},
+	}
+	if err := WriteLinesSVG(&lines, labels, series); err != nil {
+		t.Fatal(err)
+	}
+	counts := elements(t, lines.String())
+	// the background and one legend entry per series
+	if counts["polyline"] != 2 || counts["circle"] != 4 || counts["rect"] != 3 {
+		t.Errorf("WriteLinesSVG() drew %v; want 2 lines, 4 points and 2 legend entries", counts)
+	}
+	if !strings.Contains(lines.String(), `stroke="#999999"`) {
+		t.Errorf("WriteLinesSVG() did not draw Others in grey")
+	}
+
+	var scatter strings.Builder
+	if err := WriteScatterSVG(&scatter, labels, []Point{{2019, 6.1}, {2019, 6.1}, {2020, 6.7}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := elements(t, scatter.String())["circle"]; got != 3 {
+		t.Errorf("WriteScatterSVG() drew %d points; want 3", got)
+	}
+}
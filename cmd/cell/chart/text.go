@@ -0,0 +1,104 @@
+// Package chart draws bar charts, sparklines and histograms of labelled
+// values for the cell command, as text for terminals and as standalone SVG
+// documents. It knows nothing about phones: callers turn the statistics of a
+// phonedb.Catalog into Bars, Series or Points first.
+package chart
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Bar is a labelled value of a bar chart.
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// blocks are the characters of a bar that is 0 to 7 eighths of a column
+// longer than a whole number of columns.
+var blocks = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// levels are the characters of a sparkline, from the lowest value to the
+// highest.
+var levels = []rune("▁▂▃▄▅▆▇█")
+
+// WriteBars writes bars to w as a horizontal bar chart, one line per bar
+// holding its label, its value and a bar as long as the value relative to
+// the largest value. The lines are at most width columns wide, but every
+// bar gets at least ten columns. Negative values are drawn as empty bars.
+func WriteBars(w io.Writer, bars []Bar, width int) error {
+	labelWidth, valueWidth := 0, 0
+	var largest float64
+	for _, bar := range bars {
+		if n := utf8.RuneCountInString(bar.Label); n > labelWidth {
+			labelWidth = n
+		}
+		if n := len(FormatValue(bar.Value)); n > valueWidth {
+			valueWidth = n
+		}
+		if bar.Value > largest {
+			largest = bar.Value
+		}
+	}
+
+	barWidth := width - labelWidth - valueWidth - 2
+	if barWidth < 10 {
+		barWidth = 10
+	}
+	for _, bar := range bars {
+		line := fmt.Sprintf("%-*s %*s %s", labelWidth, bar.Label, valueWidth, FormatValue(bar.Value), Block(bar.Value, largest, barWidth))
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Block returns a bar of block characters as long as value relative to
+// largest, where largest fills width columns. The bar is drawn to the
+// nearest eighth of a column.
+func Block(value, largest float64, width int) string {
+	if value <= 0 || largest <= 0 {
+		return ""
+	}
+	eighths := int(math.Round(math.Min(value/largest, 1) * float64(width) * 8))
+	return strings.Repeat("█", eighths/8) + blocks[eighths%8]
+}
+
+// Sparkline returns one character per value, from ▁ for the smallest value
+// to █ for the largest, so that a trend fits on one line. All values are
+// drawn as ▁ if they are the same.
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lowest, highest := values[0], values[0]
+	for _, v := range values[1:] {
+		lowest = math.Min(lowest, v)
+		highest = math.Max(highest, v)
+	}
+
+	var line strings.Builder
+	for _, v := range values {
+		level := 0
+		if highest > lowest {
+			level = int(math.Round((v - lowest) / (highest - lowest) * float64(len(levels)-1)))
+		}
+		line.WriteRune(levels[level])
+	}
+	return line.String()
+}
+
+// FormatValue formats a value of a chart: whole numbers without decimals,
+// other numbers with up to two decimals, as in "330" and "154.86".
+func FormatValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,69 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteBars(t *testing.T) {
+	bars := []Bar{{"2018", 40}, {"2019", 300}, {"2020", 0}, {"Phones released", 154.857}}
+	var out strings.Builder
+	if err := WriteBars(&out, bars, 40); err != nil {
+		t.Fatal(err)
+	}
+	// 40 columns hold 15 for the label, 6 for the value, 17 for the bar and
+	// the spaces between them
+	want := "2018                40 ██▎\n" +
+		"2019               300 █████████████████\n" +
+		"2020                 0\n" +
+		"Phones released 154.86 ████████▊\n"
+	if out.String() != want {
+		t.Errorf("WriteBars() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	tests := []struct {
+		value, largest float64
+		width          int
+		want           string
+	}{
+		{0, 10, 10, ""},
+		{-1, 10, 10, ""},
+		{10, 10, 4, "████"},
+		{5, 10, 3, "█▌"},
+		{1, 80, 10, "▏"},
+		{20, 10, 2, "██"},
+	}
+	for _, tt := range tests {
+		if got := Block(tt.value, tt.largest, tt.width); got != tt.want {
+			t.Errorf("Block(%v, %v, %d) = %q; want %q", tt.value, tt.largest, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   string
+	}{
+		{nil, ""},
+		{[]float64{3, 3}, "▁▁"},
+		{[]float64{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+		{[]float64{10, 0, 5}, "█▁▅"},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.values); got != tt.want {
+			t.Errorf("Sparkline(%v) = %q; want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := map[float64]string{330: "330", 154.857: "154.86", 0.5: "0.5", 16000000: "16000000", -2: "-2"}
+	for v, want := range tests {
+		if got := FormatValue(v); got != want {
+			t.Errorf("FormatValue(%v) = %q; want %q", v, got, want)
+		}
+	}
+}
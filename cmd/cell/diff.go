@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"cell/phonedb"
+)
+
+// runDiff implements "cell diff", which lists the phones added, removed and
+// changed between two cells.csv files.
+func runDiff(args []string) int {
+	flags := newFlagSet("diff", "cell diff [-data file] [-format text|json] <other file>",
+		"Diff compares the phones of the -data file with those of the other file,\n"+
+			"listing added phones with \"+\", removed phones with \"-\" and changed\n"+
+			"phones with \"~\" followed by the old and new value of each changed column.")
+	data := dataFlag(flags)
+	format := formatFlag(flags)
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	before, err := loadCatalog(*data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	after, err := loadCatalog(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+
+	changes := phonedb.Diff(before, after)
+	if *format == "json" {
+		docs := make([]phonedb.ChangeDocument, len(changes))
+		for i, change := range changes {
+			docs[i] = change.Document()
+		}
+		if err := writeJSON(os.Stdout, phonedb.NewDocument("diff", *data, docs)); err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 1
+		}
+		return 0
+	}
+
+	var added, removed, changed int
+	for _, change := range changes {
+		fmt.Println(change)
+		switch {
+		case change.Before == nil:
+			added++
+		case change.After == nil:
+			removed++
+		default:
+			changed++
+			oldRecord, newRecord := change.Before.Record(), change.After.Record()
+			for i, name := range phonedb.RequiredColumns {
+				if oldRecord[i] != newRecord[i] {
+					fmt.Printf("    %s: %q -> %q\n", name, oldRecord[i], newRecord[i])
+				}
+			}
+		}
+	}
+	fmt.Printf("%d added, %d removed, %d changed\n", added, removed, changed)
+	return 0
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// runExport implements "cell export", which writes the phones as they were
+// loaded, with placeholders cleared and duplicates resolved, in cells.csv
+// format.
+func runExport(args []string) int {
+	flags := newFlagSet("export", "cell export [-data file] [-o file] [-where expr]",
+		"Export writes the cleaned phones in cells.csv format, one row per phone\n"+
+			"ordered by OEM and model. Values that could not be parsed are left empty.")
+	data := dataFlag(flags)
+	output := flags.String("o", "", "write to `file` instead of stdout")
+	where := whereFlag(flags)
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	cells, err := loadCatalog(*data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+
+	if err := writeOutput(*output, cells.Where(where).WriteCSV); err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	return 0
+}
+
+// writeOutput calls write with the named file, created or truncated, or with
+// stdout if path is empty.
+func writeOutput(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
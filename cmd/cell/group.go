@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"cell/phonedb"
+)
+
+// runGroup implements "cell group", which splits the phones into groups by
+// one or more fields and prints aggregates of every group.
+func runGroup(args []string) int {
+	flags := newFlagSet("group", "cell group [-data file] [-format text|json] [-where expr] -by field[,field...] [aggregate...]",
+		"Group splits the phones into groups with the same values of the -by fields\n"+
+			"and prints one line per group with each aggregate, as in\n"+
+			"\"cell group -by oem,year count mean:display_size\".\n\n"+
+			"An aggregate is count, or one of count, sum, mean, median, min, max,\n"+
+			"stddev, iqr, p5, p25, p75, p95 and argmax followed by a colon and a\n"+
+			"numeric field, as in p95:weight. count:field counts the phones the field\n"+
+			"is known for. The fields are those filter expressions accept, including\n"+
+			"decade. The default aggregate is count.")
+	data := dataFlag(flags)
+	format := formatFlag(flags)
+	by := flags.String("by", "", "comma separated `fields` to group by, such as oem,decade")
+	where := whereFlag(flags)
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if *by == "" {
+		flags.Usage()
+		return 2
+	}
+	fields := strings.Split(*by, ",")
+	key, err := phonedb.FieldKey(fields...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell: -by:", err)
+		return 2
+	}
+	specs := flags.Args()
+	if len(specs) == 0 {
+		specs = []string{"count"}
+	}
+
+	cells, err := loadCatalog(*data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	cells = cells.Where(where)
+
+	columns := make([]phonedb.Result[map[phonedb.FieldValues]any], len(specs))
+	for i, spec := range specs {
+		if columns[i], err = groupColumn(cells, key, spec); err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 2
+		}
+	}
+
+	// every group with at least one aggregate, ordered by its values
+	seen := make(map[phonedb.FieldValues]bool)
+	var groups []phonedb.FieldValues
+	for _, column := range columns {
+		for group := range column.Value {
+			if !seen[group] {
+				seen[group] = true
+				groups = append(groups, group)
+			}
+		}
+	}
+	phonedb.SortFieldValues(groups)
+
+	if *format == "json" {
+		doc := phonedb.GroupsDocument{By: fields, Aggregates: make([]phonedb.AggregateDocument, len(specs)), Groups: []phonedb.GroupDocument{}}
+		for i, spec := range specs {
+			doc.Aggregates[i] = phonedb.AggregateDocument{Name: spec, Included: columns[i].Included, Excluded: columns[i].Excluded}
+		}
+		for _, group := range groups {
+			values := make(map[string]any, len(specs))
+			for i, spec := range specs {
+				values[spec] = columns[i].Value[group]
+			}
+			doc.Groups = append(doc.Groups, phonedb.GroupDocument{Key: group.Values(), Values: values})
+		}
+		if err := writeJSON(os.Stdout, phonedb.NewDocument("groups", *data, doc)); err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 1
+		}
+		return 0
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, strings.Join(append(append([]string{}, fields...), specs...), "\t"))
+	for _, group := range groups {
+		row := group.Values()
+		for _, column := range columns {
+			row = append(row, formatAggregate(column.Value[group]))
+		}
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	// like the statistics, each aggregate notes the phones it left out
+	for i, spec := range specs {
+		if columns[i].Excluded > 0 {
+			fmt.Printf("%s: %d phones without the fields it needs excluded\n", spec, columns[i].Excluded)
+		}
+	}
+	return 0
+}
+
+// aggregateFuncs are the aggregates of a numeric field "cell group"
+// computes, by name.
+var aggregateFuncs = map[string]func(phonedb.Metric) phonedb.Aggregate[float64]{
+	"sum":    phonedb.Sum,
+	"mean":   phonedb.Mean,
+	"median": phonedb.Median,
+	"min":    phonedb.Min,
+	"max":    phonedb.Max,
+	"stddev": phonedb.StdDev,
+	"iqr":    phonedb.IQR,
+	"p5":     func(m phonedb.Metric) phonedb.Aggregate[float64] { return phonedb.Percentile(m, 5) },
+	"p25":    func(m phonedb.Metric) phonedb.Aggregate[float64] { return phonedb.Percentile(m, 25) },
+	"p75":    func(m phonedb.Metric) phonedb.Aggregate[float64] { return phonedb.Percentile(m, 75) },
+	"p95":    func(m phonedb.Metric) phonedb.Aggregate[float64] { return phonedb.Percentile(m, 95) },
+}
+
+// groupColumn computes the aggregate spec, such as "count" or
+// "mean:weight", over the phones in each group.
+func groupColumn(cells *phonedb.Catalog, key func(*phonedb.Cell) (phonedb.FieldValues, bool), spec string) (phonedb.Result[map[phonedb.FieldValues]any], error) {
+	name, field, hasField := strings.Cut(spec, ":")
+	if name == "count" && !hasField {
+		return anyValues(phonedb.GroupBy(cells, key, phonedb.Count())), nil
+	}
+	if _, ok := aggregateFuncs[name]; !ok && name != "argmax" && name != "count" {
+		return phonedb.Result[map[phonedb.FieldValues]any]{}, fmt.Errorf("unknown aggregate %q, want count, sum, mean, median, min, max, stddev, iqr, p5, p25, p75, p95 or argmax", spec)
+	}
+	if field == "" {
+		return phonedb.Result[map[phonedb.FieldValues]any]{}, fmt.Errorf("aggregate %q needs a field, as in %q", spec, name+":weight")
+	}
+	metric, err := phonedb.FieldMetric(field)
+	if err != nil {
+		return phonedb.Result[map[phonedb.FieldValues]any]{}, fmt.Errorf("aggregate %q: %w", spec, err)
+	}
+	if name == "count" {
+		return anyValues(phonedb.GroupBy(cells, key, phonedb.CountKnown(metric))), nil
+	}
+	if name == "argmax" {
+		phones := phonedb.GroupBy(cells, key, phonedb.ArgMax(metric))
+		details := phonedb.Result[map[phonedb.FieldValues]phonedb.PhoneDetails]{
+			Value:    make(map[phonedb.FieldValues]phonedb.PhoneDetails, len(phones.Value)),
+			Included: phones.Included,
+			Excluded: phones.Excluded,
+		}
+		for group, cell := range phones.Value {
+			details.Value[group] = phonedb.PhoneDetails{OEM: cell.OEM(), Model: cell.Model()}
+		}
+		return anyValues(details), nil
+	}
+	return anyValues(phonedb.GroupBy(cells, key, aggregateFuncs[name](metric))), nil
+}
+
+// anyValues returns r with the values of its map as any, so that
+// aggregates of different types can be printed side by side.
+func anyValues[V any](r phonedb.Result[map[phonedb.FieldValues]V]) phonedb.Result[map[phonedb.FieldValues]any] {
+	values := make(map[phonedb.FieldValues]any, len(r.Value))
+	for group, v := range r.Value {
+		values[group] = v
+	}
+	return phonedb.Result[map[phonedb.FieldValues]any]{Value: values, Included: r.Included, Excluded: r.Excluded}
+}
+
+// formatAggregate returns the text of an aggregate, or "-" if the group has
+// none.
+func formatAggregate(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case float64:
+		return fmt.Sprintf("%.2f", v)
+	case phonedb.PhoneDetails:
+		return v.OEM + " " + v.Model
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"cell/phonedb"
+)
+
+// runShow implements "cell show <oem> <model>", which prints every field of
+// one phone.
+func runShow(args []string) int {
+	flags := newFlagSet("show", "cell show [-data file] [-format text|json] <oem> <model>",
+		"Show prints every field of the phone with the given OEM and model.\n"+
+			"Names are matched exactly first, then ignoring case.")
+	data := dataFlag(flags)
+	format := formatFlag(flags)
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	oem, model := flags.Arg(0), flags.Arg(1)
+
+	cells, err := loadCatalog(*data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+
+	cell := cells.Get(oem, model)
+	if cell == nil {
+		for _, c := range cells.Phones() {
+			if strings.EqualFold(c.OEM(), oem) && strings.EqualFold(c.Model(), model) {
+				cell = c
+				break
+			}
+		}
+	}
+	if cell == nil {
+		fmt.Fprintf(os.Stderr, "cell: no phone %q by %q in %s\n", model, oem, *data)
+		return 1
+	}
+	if *format == "json" {
+		if err := writeJSON(os.Stdout, phonedb.NewDocument("phone", *data, cell.Document())); err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 1
+		}
+		return 0
+	}
+	fmt.Print(strings.TrimPrefix(cell.String(), "\n"))
+	return 0
+}
+
+// runList implements "cell list", which lists the phones one per line.
+func runList(args []string) int {
+	flags := newFlagSet("list", "cell list [-data file] [-format text|json] [-sort name|latest] [-oem name] [-where expr]",
+		"List prints the OEM, model, announcement year and status of every phone,\n"+
+			"ordered by OEM and model unless -sort says otherwise.")
+	data := dataFlag(flags)
+	format := formatFlag(flags)
+	order := sortFlag(flags, "phones: name (OEM, then model) or latest\n(most recently announced first); ties are broken by name")
+	oem := flags.String("oem", "", "list only the phones made by `name`, ignoring case")
+	where := whereFlag(flags)
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	cells, err := loadCatalog(*data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+
+	var phones []*phonedb.Cell
+	for _, cell := range cells.Where(where).Phones() {
+		if *oem == "" || strings.EqualFold(cell.OEM(), *oem) {
+			phones = append(phones, cell)
+		}
+	}
+	phonedb.SortPhones(phones, *order)
+	if err := printPhones(*format, *data, phones); err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	return 0
+}
+
+// runSearch implements "cell search <words>", which lists the phones whose
+// OEM and model contain every word.
+func runSearch(args []string) int {
+	flags := newFlagSet("search", "cell search [-data file] [-format text|json] [-sort name|latest] [-where expr] <word>...",
+		"Search lists the phones whose OEM and model together contain every word,\n"+
+			"ignoring case, as in \"cell search pixel xl\".")
+	data := dataFlag(flags)
+	format := formatFlag(flags)
+	order := sortFlag(flags, "phones: name (OEM, then model) or latest\n(most recently announced first); ties are broken by name")
+	where := whereFlag(flags)
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	cells, err := loadCatalog(*data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+
+	phones := cells.Where(where).Search(strings.Join(flags.Args(), " "))
+	phonedb.SortPhones(phones, *order)
+	if err := printPhones(*format, *data, phones); err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	if len(phones) == 0 {
+		fmt.Fprintln(os.Stderr, "cell: no phone matches", strings.Join(flags.Args(), " "))
+		return 1
+	}
+	return 0
+}
+
+// printPhones prints the phones read from source, as a JSON document of
+// every field or as a table of their announcement year and status.
+func printPhones(format outputFormat, source string, phones []*phonedb.Cell) error {
+	if format == "json" {
+		return writeJSON(os.Stdout, phonedb.NewDocument("phones", source, phonedb.PhoneDocuments(phones)))
+	}
+	if len(phones) == 0 {
+		return nil
+	}
+	fmt.Printf("%-15s %-35s %-10s %s\n", "OEM", "Model", "Announced", "Status")
+	for _, cell := range phones {
+		fmt.Printf("%-15s %-35s %-10s %s\n", cell.OEM(), cell.Model(), cell.LaunchAnnounced(), cell.LaunchStatus())
+	}
+	return nil
+}
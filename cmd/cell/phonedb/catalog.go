@@ -0,0 +1,215 @@
+package phonedb
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Catalog is a collection of phones keyed by their OEM and model.
+type Catalog struct {
+	cells map[string]*Cell
+}
+
+// NewCatalog creates an empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{cells: make(map[string]*Cell)}
+}
+
+// Key returns the catalog key for the given OEM and model, which is the
+// manufacturer and model of the phone separated by a dash.
+func Key(oem, model string) string {
+	return fmt.Sprintf("%s-%s", oem, model)
+}
+
+// DuplicatePolicy selects what Catalog.Insert does with a phone whose key
+// is already in the catalog.
+type DuplicatePolicy int
+
+const (
+	// KeepLast replaces the phone already in the catalog.
+	KeepLast DuplicatePolicy = iota
+	// KeepFirst drops the phone being added.
+	KeepFirst
+	// KeepBoth stores the phone being added under a numbered key such as
+	// "Google-Pixel 4 XL (2)".
+	KeepBoth
+	// MergeFields fills the unknown fields of the phone already in the
+	// catalog from the phone being added.
+	MergeFields
+)
+
+// String implements the Stringer interface for DuplicatePolicy.
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case KeepFirst:
+		return "keep first"
+	case KeepBoth:
+		return "keep both"
+	case MergeFields:
+		return "merge"
+	default:
+		return "keep last"
+	}
+}
+
+// Collision describes a phone added under a key the catalog already held.
+type Collision struct {
+	// key both phones share
+	Key string
+	// phone that was in the catalog
+	Existing *Cell
+	// phone that was being added
+	Added *Cell
+	// whether the two phones have the same value in every field
+	Identical bool
+	// key the added phone, or the merged phone, was stored under; empty if
+	// the added phone was dropped
+	StoredAs string
+	// policy that resolved the collision
+	Policy DuplicatePolicy
+}
+
+// Add inserts cell into the catalog under its OEM and model key, replacing
+// any phone already stored under that key.
+func (c *Catalog) Add(cell *Cell) {
+	c.Insert(cell, KeepLast)
+}
+
+// Insert adds cell to the catalog under its OEM and model key. If the key
+// is taken the collision is resolved according to policy and described in
+// the returned Collision; otherwise Insert returns nil.
+func (c *Catalog) Insert(cell *Cell, policy DuplicatePolicy) *Collision {
+	key := Key(cell.oem, cell.model)
+	existing, ok := c.cells[key]
+	if !ok {
+		c.cells[key] = cell
+		return nil
+	}
+
+	collision := &Collision{
+		Key:       key,
+		Existing:  existing,
+		Added:     cell,
+		Identical: sameFields(existing, cell),
+		StoredAs:  key,
+		Policy:    policy,
+	}
+	switch policy {
+	case KeepFirst:
+		collision.StoredAs = ""
+	case KeepBoth:
+		// the first free numbered key, starting from 2 for the second phone
+		for n := 2; ; n++ {
+			numbered := fmt.Sprintf("%s (%d)", key, n)
+			if _, taken := c.cells[numbered]; !taken {
+				c.cells[numbered] = cell
+				collision.StoredAs = numbered
+				break
+			}
+		}
+	case MergeFields:
+		c.cells[key] = merge(existing, cell)
+	default:
+		c.cells[key] = cell
+	}
+	return collision
+}
+
+// sameFields reports whether two phones have the same value in every field,
+// wherever in the file they were loaded from.
+func sameFields(a, b *Cell) bool {
+	x, y := *a, *b
+	x.line, y.line = 0, 0
+	return reflect.DeepEqual(x, y)
+}
+
+// merge returns a copy of first with every unknown field taken from second.
+func merge(first, second *Cell) *Cell {
+	merged := *first
+	if !merged.launch.Announced.Known() {
+		merged.launch.Announced = second.launch.Announced
+	}
+	if !merged.launch.Released.Known() {
+		merged.launch.Released = second.launch.Released
+	}
+	if merged.launch.Status == StatusUnknown {
+		merged.launch.Status = second.launch.Status
+	}
+	mergeField(&merged.bodyDimensions, second.bodyDimensions)
+	mergeField(&merged.bodyWeight, second.bodyWeight)
+	mergeField(&merged.bodySim, second.bodySim)
+	mergeField(&merged.displayType, second.displayType)
+	mergeField(&merged.displaySize, second.displaySize)
+	mergeField(&merged.displayResolution, second.displayResolution)
+	mergeField(&merged.featuresSensors, second.featuresSensors)
+	mergeField(&merged.platformOS, second.platformOS)
+	return &merged
+}
+
+// mergeField sets *field to other if *field is unknown.
+func mergeField[T any](field *Optional[T], other Optional[T]) {
+	if !field.Known() {
+		*field = other
+	}
+}
+
+// Get returns the phone with the given OEM and model, or nil if the
+// catalog does not contain it.
+func (c *Catalog) Get(oem, model string) *Cell {
+	return c.cells[Key(oem, model)]
+}
+
+// Len returns the number of phones in the catalog.
+func (c *Catalog) Len() int {
+	return len(c.cells)
+}
+
+// Cells returns the phones in the catalog keyed by OEM and model.
+// The returned map must not be modified.
+func (c *Catalog) Cells() map[string]*Cell {
+	return c.cells
+}
+
+// Phones returns the phones in the catalog ordered by OEM, then model, then
+// the line they were loaded from.
+func (c *Catalog) Phones() []*Cell {
+	phones := make([]*Cell, 0, len(c.cells))
+	for _, cell := range c.cells {
+		phones = append(phones, cell)
+	}
+	sort.Slice(phones, func(i, j int) bool {
+		a, b := phones[i], phones[j]
+		if a.oem != b.oem {
+			return a.oem < b.oem
+		}
+		if a.model != b.model {
+			return a.model < b.model
+		}
+		return a.line < b.line
+	})
+	return phones
+}
+
+// Search returns the phones whose OEM and model together contain every
+// word of query, ignoring case, in Phones order. An empty query matches
+// every phone.
+func (c *Catalog) Search(query string) []*Cell {
+	words := strings.Fields(strings.ToLower(query))
+	var found []*Cell
+	for _, cell := range c.Phones() {
+		name := strings.ToLower(cell.oem + " " + cell.model)
+		matches := true
+		for _, word := range words {
+			if !strings.Contains(name, word) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			found = append(found, cell)
+		}
+	}
+	return found
+}
@@ -0,0 +1,87 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsert(t *testing.T) {
+	first := &Cell{oem: "Samsung", model: "Galaxy S10", bodyWeight: Some[float64](157)}
+	second := &Cell{oem: "Samsung", model: "Galaxy S10", displaySize: Some(6.1), bodyWeight: Some[float64](160)}
+
+	tests := []struct {
+		policy       DuplicatePolicy
+		wantCells    map[string]*Cell
+		wantStoredAs string
+	}{
+		{KeepLast, map[string]*Cell{"Samsung-Galaxy S10": second}, "Samsung-Galaxy S10"},
+		{KeepFirst, map[string]*Cell{"Samsung-Galaxy S10": first}, ""},
+		{KeepBoth, map[string]*Cell{"Samsung-Galaxy S10": first, "Samsung-Galaxy S10 (2)": second}, "Samsung-Galaxy S10 (2)"},
+		{
+			MergeFields,
+			map[string]*Cell{"Samsung-Galaxy S10": {oem: "Samsung", model: "Galaxy S10", bodyWeight: Some[float64](157), displaySize: Some(6.1)}},
+			"Samsung-Galaxy S10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			cells := NewCatalog()
+			if collision := cells.Insert(first, tt.policy); collision != nil {
+				t.Fatalf("cells.Insert(first) = %+v; want nil", collision)
+			}
+
+			collision := cells.Insert(second, tt.policy)
+			if collision == nil {
+				t.Fatalf("cells.Insert(second) = nil; want a collision")
+			}
+			if collision.Key != "Samsung-Galaxy S10" || collision.Identical || collision.StoredAs != tt.wantStoredAs {
+				t.Errorf("cells.Insert(second) = %+v; want key Samsung-Galaxy S10, not identical, stored as %q", collision, tt.wantStoredAs)
+			}
+			if !reflect.DeepEqual(cells.Cells(), tt.wantCells) {
+				t.Errorf("cells.Cells() = %v; want %v", cells.Cells(), tt.wantCells)
+			}
+		})
+	}
+}
+
+func TestInsertKeepBothNumbersEveryDuplicate(t *testing.T) {
+	cells := NewCatalog()
+	for i := 0; i < 3; i++ {
+		cells.Insert(&Cell{oem: "Nokia", model: "3310"}, KeepBoth)
+	}
+
+	for _, key := range []string{"Nokia-3310", "Nokia-3310 (2)", "Nokia-3310 (3)"} {
+		if cells.Cells()[key] == nil {
+			t.Errorf("cells.Cells()[%q] = nil; want a phone", key)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Google-Pixel 4 XL":  {oem: "Google", model: "Pixel 4 XL"},
+		"Google-Pixel 3a XL": {oem: "Google", model: "Pixel 3a XL"},
+		"Google-Pixel 4":     {oem: "Google", model: "Pixel 4"},
+		"LG-G8 ThinQ":        {oem: "LG", model: "G8 ThinQ"},
+	}}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"pixel xl", []string{"Pixel 3a XL", "Pixel 4 XL"}},
+		{"GOOGLE 4", []string{"Pixel 4", "Pixel 4 XL"}},
+		{"", []string{"Pixel 3a XL", "Pixel 4", "Pixel 4 XL", "G8 ThinQ"}},
+		{"nokia", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, cell := range cells.Search(tt.query) {
+			got = append(got, cell.model)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("cells.Search(%q) = %q; want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package phonedb holds the Cell model for mobile phones, the parsers used to
+// turn the raw cells.csv columns into typed values and the aggregate
+// statistics computed over a Catalog of phones.
+package phonedb
+
+import (
+	"fmt"
+	"math"
+)
+
+// Cell represents a mobile phone with various properties.
+type Cell struct {
+	// company phone comes from
+	oem string
+	// model of phone
+	model string
+	// when the phone was announced and released, and its availability
+	launch LaunchInfo
+	// dimensions of the phone's body
+	bodyDimensions Optional[Dimensions]
+	// weight of phone's body
+	bodyWeight Optional[float64]
+	// type of sim card
+	bodySim Optional[SimConfig]
+	// type of display
+	displayType Optional[DisplayType]
+	// size of display in inches
+	displaySize Optional[float64]
+	// resolution of display
+	displayResolution Optional[Resolution]
+	// any features that are sensors
+	featuresSensors Optional[Sensors]
+	// platform of the operating system of the phone
+	platformOS Optional[OSInfo]
+	// line of the file the phone was loaded from, 0 if it was not loaded
+	line int
+}
+
+// NewCell creates a new Cell with the given properties. Properties missing
+// from the dataset are passed as unknown Optional values.
+func NewCell(oem string, model string, launch LaunchInfo,
+	bodyDimensions Optional[Dimensions], bodyWeight Optional[float64],
+	bodySim Optional[SimConfig], displayType Optional[DisplayType], displaySize Optional[float64],
+	displayResolution Optional[Resolution], featuresSensors Optional[Sensors], platformOS Optional[OSInfo]) *Cell {
+	return &Cell{
+		oem:               oem,
+		model:             model,
+		launch:            launch,
+		bodyDimensions:    bodyDimensions,
+		bodyWeight:        bodyWeight,
+		bodySim:           bodySim,
+		displayType:       displayType,
+		displaySize:       displaySize,
+		displayResolution: displayResolution,
+		featuresSensors:   featuresSensors,
+		platformOS:        platformOS,
+	}
+}
+
+// OEM returns the company the phone comes from.
+func (c *Cell) OEM() string { return c.oem }
+
+// Model returns the model name of the phone.
+func (c *Cell) Model() string { return c.model }
+
+// Line returns the line of the file the phone was loaded from, counting the
+// header as line 1, or 0 if it was not loaded from a file.
+func (c *Cell) Line() int { return c.line }
+
+// Launch returns the announcement and release dates and the launch status.
+func (c *Cell) Launch() LaunchInfo { return c.launch }
+
+// LaunchAnnounced returns the year the launch was announced.
+func (c *Cell) LaunchAnnounced() Optional[uint] { return c.launch.Announced.year() }
+
+// LaunchStatus returns the availability of the phone.
+func (c *Cell) LaunchStatus() Status { return c.launch.Status }
+
+// BodyDimensions returns the dimensions of the phone's body.
+func (c *Cell) BodyDimensions() Optional[Dimensions] { return c.bodyDimensions }
+
+// BodyWeight returns the body weight in grams.
+func (c *Cell) BodyWeight() Optional[float64] { return c.bodyWeight }
+
+// BodySim returns the SIM card configuration.
+func (c *Cell) BodySim() Optional[SimConfig] { return c.bodySim }
+
+// DisplayType returns the panel technology, touch kind and colors of the display.
+func (c *Cell) DisplayType() Optional[DisplayType] { return c.displayType }
+
+// DisplaySize returns the display size in inches.
+func (c *Cell) DisplaySize() Optional[float64] { return c.displaySize }
+
+// DisplayResolution returns the resolution of the display.
+func (c *Cell) DisplayResolution() Optional[Resolution] { return c.displayResolution }
+
+// PixelDensity returns the pixel density of the display in pixels per inch.
+// The declared density is preferred, otherwise it is computed from the pixel
+// dimensions and display size. It is unknown if neither is available.
+func (c *Cell) PixelDensity() Optional[float64] {
+	res, ok := c.displayResolution.Get()
+	if !ok {
+		return Optional[float64]{}
+	}
+	if res.PPI > 0 {
+		return Some(float64(res.PPI))
+	}
+	size, ok := c.displaySize.Get()
+	if !res.HasPixels() || !ok || size <= 0 {
+		return Optional[float64]{}
+	}
+	diagonal := math.Hypot(float64(res.Width), float64(res.Height))
+	return Some(diagonal / size)
+}
+
+// FeaturesSensors returns the set of sensors the phone has.
+func (c *Cell) FeaturesSensors() Optional[Sensors] { return c.featuresSensors }
+
+// PlatformOS returns the operating system of the phone.
+func (c *Cell) PlatformOS() Optional[OSInfo] { return c.platformOS }
+
+// String implements the Stringer interface for the Cell struct.
+func (c Cell) String() string {
+	weight, size := "-", "-"
+	if w, ok := c.bodyWeight.Get(); ok {
+		weight = fmt.Sprintf("%.2f g", w)
+	}
+	if s, ok := c.displaySize.Get(); ok {
+		size = fmt.Sprintf("%.2f in", s)
+	}
+	return fmt.Sprintf("\nOEM: %s\nModel: %s\nLaunch Announced: %s\nLaunch Status: %s\nBody Dimensions: %s\nBody Weight: %s\nSIM: %s\nDisplay Type: %s\nDisplay Size: %s\nDisplay Resolution: %s\nSensors: %s\nPlatform OS: %s\n", c.oem, c.model, c.launch.Announced, c.launch, c.bodyDimensions, weight, c.bodySim, c.displayType, size, c.displayResolution, c.featuresSensors, c.platformOS)
+}
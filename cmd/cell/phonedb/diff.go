@@ -0,0 +1,65 @@
+package phonedb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change describes a phone that differs between two catalogs.
+type Change struct {
+	// catalog key of the phone
+	Key string
+	// phone in the first catalog, nil if the phone was added
+	Before *Cell
+	// phone in the second catalog, nil if the phone was removed
+	After *Cell
+	// columns whose values differ, in RequiredColumns order; empty for
+	// added and removed phones
+	Columns []string
+}
+
+// String implements the Stringer interface for Change, marking added phones
+// with "+", removed phones with "-" and changed phones with "~".
+func (c Change) String() string {
+	switch {
+	case c.Before == nil:
+		return "+ " + c.Key
+	case c.After == nil:
+		return "- " + c.Key
+	default:
+		return fmt.Sprintf("~ %s: %s", c.Key, strings.Join(c.Columns, ", "))
+	}
+}
+
+// Diff compares two catalogs by key and returns the phones that were added,
+// removed or changed going from before to after, ordered by key. Phones are
+// compared column by column as WriteCSV would write them, so a change in
+// the line a phone was loaded from is not a change.
+func Diff(before, after *Catalog) []Change {
+	var changes []Change
+	for key, old := range before.cells {
+		cell, ok := after.cells[key]
+		if !ok {
+			changes = append(changes, Change{Key: key, Before: old})
+			continue
+		}
+		oldRecord, newRecord := old.Record(), cell.Record()
+		var columns []string
+		for i, name := range RequiredColumns {
+			if oldRecord[i] != newRecord[i] {
+				columns = append(columns, name)
+			}
+		}
+		if len(columns) > 0 {
+			changes = append(changes, Change{Key: key, Before: old, After: cell, Columns: columns})
+		}
+	}
+	for key, cell := range after.cells {
+		if _, ok := before.cells[key]; !ok {
+			changes = append(changes, Change{Key: key, After: cell})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
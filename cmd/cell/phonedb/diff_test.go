@@ -0,0 +1,33 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := &Catalog{cells: map[string]*Cell{
+		"Google-Pixel":  {oem: "Google", model: "Pixel", bodyWeight: Some[float64](143), line: 2},
+		"Nokia-3310":    {oem: "Nokia", model: "3310", bodyWeight: Some[float64](133)},
+		"Sony-Xperia 1": {oem: "Sony", model: "Xperia 1", displaySize: Some(6.5)},
+	}}
+	after := &Catalog{cells: map[string]*Cell{
+		// moving a phone to another line is not a change
+		"Google-Pixel":  {oem: "Google", model: "Pixel", bodyWeight: Some[float64](143), line: 7},
+		"Sony-Xperia 1": {oem: "Sony", model: "Xperia 1", bodyWeight: Some[float64](178), displaySize: Some(6.1)},
+		"Apple-iPhone":  {oem: "Apple", model: "iPhone"},
+	}}
+
+	var got []string
+	for _, change := range Diff(before, after) {
+		got = append(got, change.String())
+	}
+	want := []string{
+		"+ Apple-iPhone",
+		"- Nokia-3310",
+		"~ Sony-Xperia 1: body_weight, display_size",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package phonedb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Dimensions holds the size of a phone's body in millimetres. A measure that
+// is not known is zero.
+type Dimensions struct {
+	// longest side of the body
+	Height float64
+	// shorter side of the body
+	Width float64
+	// thickness of the body
+	Depth float64
+}
+
+// measure matches a single length in millimetres, which may be a range such
+// as "8.5-24".
+const measure = `(\d+(?:\.\d+)?(?:\s*-\s*\d+(?:\.\d+)?)?)`
+
+var (
+	// "145 x 56 x 23 mm (5.71 x 2.20 x 0.91 in)" or "242mm x 166mm x 8.5-24mm"
+	dimensionsRe = regexp.MustCompile(`^` + measure + `(?:\s*mm)?\s*x\s*` + measure + `(?:\s*mm)?\s*x\s*` + measure + `\s*mm`)
+	// "7.9 mm thickness"
+	thicknessRe = regexp.MustCompile(`^` + measure + `\s*mm thickness`)
+)
+
+// ParseDimensions extracts the height, width and depth in millimetres from a
+// body_dimensions string. For foldable phones the folded dimensions are used,
+// and a range such as "8.5-24mm" is taken at its largest value so the result
+// is the bounding box of the body. If no dimensions are found, as for "-" or
+// "V1", it returns nil.
+func ParseDimensions(dimStr string) *Dimensions {
+	// Foldables list "Unfolded: ... mmFolded: ... mm", keep the folded part
+	if i := strings.LastIndex(dimStr, "Folded:"); i >= 0 {
+		dimStr = dimStr[i+len("Folded:"):]
+	}
+	dimStr = strings.TrimSpace(dimStr)
+
+	if match := dimensionsRe.FindStringSubmatch(dimStr); match != nil {
+		return &Dimensions{
+			Height: parseMeasure(match[1]),
+			Width:  parseMeasure(match[2]),
+			Depth:  parseMeasure(match[3]),
+		}
+	}
+
+	if match := thicknessRe.FindStringSubmatch(dimStr); match != nil {
+		return &Dimensions{Depth: parseMeasure(match[1])}
+	}
+
+	return nil
+}
+
+// parseMeasure converts a length matched by measure to a float, taking the
+// upper bound of a range.
+func parseMeasure(s string) float64 {
+	parts := strings.Split(s, "-")
+	value, err := strconv.ParseFloat(strings.TrimSpace(parts[len(parts)-1]), 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// Volume returns the volume of the bounding box of the body in cubic
+// centimetres, or 0 if any of the three measures is unknown.
+func (d Dimensions) Volume() float64 {
+	return d.Height * d.Width * d.Depth / 1000
+}
+
+// String implements the Stringer interface for Dimensions.
+func (d Dimensions) String() string {
+	switch {
+	case d.Height > 0 && d.Width > 0 && d.Depth > 0:
+		return fmt.Sprintf("%g x %g x %g mm", d.Height, d.Width, d.Depth)
+	case d.Depth > 0:
+		return fmt.Sprintf("%g mm thickness", d.Depth)
+	default:
+		return "-"
+	}
+}
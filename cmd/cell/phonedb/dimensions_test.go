@@ -0,0 +1,39 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDimensions(t *testing.T) {
+	tests := []struct {
+		input string
+		want  *Dimensions
+	}{
+		{"145 x 56 x 23 mm (5.71 x 2.20 x 0.91 in)", &Dimensions{145, 56, 23}},
+		{"140 x 50 x 25 mm, 150 cc (5.51 x 1.97 x 0.98 in)", &Dimensions{140, 50, 25}},
+		{"242mm x 166mm x 8.5-24mm", &Dimensions{242, 166, 24}},
+		{"Unfolded: 172 x 72 x 6.9 mmFolded: 94 x 72 x 14mm", &Dimensions{94, 72, 14}},
+		{"7.9 mm thickness", &Dimensions{Depth: 7.9}},
+		{"-", nil},
+		{"V1", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		got := ParseDimensions(test.input)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseDimensions(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestDimensionsVolume(t *testing.T) {
+	if got := (Dimensions{100, 50, 10}).Volume(); got != 50 {
+		t.Errorf("Volume() = %v, want 50", got)
+	}
+	if got := (Dimensions{Depth: 7.9}).Volume(); got != 0 {
+		t.Errorf("Volume() with unknown height = %v, want 0", got)
+	}
+}
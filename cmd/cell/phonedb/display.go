@@ -0,0 +1,235 @@
+package phonedb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Panel is the technology of a phone's display panel.
+type Panel int
+
+const (
+	// PanelUnknown means the panel technology is not listed.
+	PanelUnknown Panel = iota
+	// PanelIPSLCD is an in-plane switching LCD.
+	PanelIPSLCD
+	// PanelLCD is an LCD of an unspecified kind.
+	PanelLCD
+	// PanelTFT is a thin-film transistor LCD.
+	PanelTFT
+	// PanelTFD is a thin-film diode LCD.
+	PanelTFD
+	// PanelSTN is a super-twisted nematic LCD, including color STN.
+	PanelSTN
+	// PanelOLED is an OLED panel, including P-OLED and G-OLED.
+	PanelOLED
+	// PanelAMOLED is an active-matrix OLED panel, including Super AMOLED.
+	PanelAMOLED
+	// PanelMonochrome is a monochrome or grayscale panel.
+	PanelMonochrome
+	// PanelOther is a less common technology such as UFB or CGS.
+	PanelOther
+)
+
+// String implements the Stringer interface for Panel.
+func (p Panel) String() string {
+	switch p {
+	case PanelIPSLCD:
+		return "IPS LCD"
+	case PanelLCD:
+		return "LCD"
+	case PanelTFT:
+		return "TFT"
+	case PanelTFD:
+		return "TFD"
+	case PanelSTN:
+		return "STN"
+	case PanelOLED:
+		return "OLED"
+	case PanelAMOLED:
+		return "AMOLED"
+	case PanelMonochrome:
+		return "Monochrome"
+	case PanelOther:
+		return "Other"
+	default:
+		return "Unknown"
+	}
+}
+
+// Touch is the kind of touchscreen a display has.
+type Touch int
+
+const (
+	// TouchNone means the display is not a touchscreen.
+	TouchNone Touch = iota
+	// TouchCapacitive is a capacitive touchscreen.
+	TouchCapacitive
+	// TouchResistive is a resistive touchscreen.
+	TouchResistive
+	// TouchUnspecified is a touchscreen of an unlisted kind.
+	TouchUnspecified
+)
+
+// String implements the Stringer interface for Touch.
+func (t Touch) String() string {
+	switch t {
+	case TouchCapacitive:
+		return "capacitive"
+	case TouchResistive:
+		return "resistive"
+	case TouchUnspecified:
+		return "touchscreen"
+	default:
+		return "none"
+	}
+}
+
+// DisplayType describes the technology of a phone's display. A count that is
+// not known is zero.
+type DisplayType struct {
+	Panel Panel
+	Touch Touch
+	// number of colors as declared, so "65K colors" is 65000
+	Colors int
+	// number of gray shades of a grayscale display
+	Shades int
+}
+
+// panelNames maps words in display_type to panel technologies. Prefixes
+// such as the "P-" in "P-OLED" are dropped before the lookup.
+var panelNames = map[string]Panel{
+	"ips":          PanelIPSLCD,
+	"amoled":       PanelAMOLED,
+	"oled":         PanelOLED,
+	"cstn":         PanelSTN,
+	"stn":          PanelSTN,
+	"tft":          PanelTFT,
+	"tfd":          PanelTFD,
+	"lcd":          PanelLCD,
+	"monochrome":   PanelMonochrome,
+	"grayscale":    PanelMonochrome,
+	"greyscale":    PanelMonochrome,
+	"alphanumeric": PanelMonochrome,
+	"ufb":          PanelOther,
+	"ufs":          PanelOther,
+	"cgs":          PanelOther,
+	"gfc":          PanelOther,
+	"csn":          PanelOther,
+	// written by DisplayType.String, so exported data reads back
+	"other": PanelOther,
+}
+
+var (
+	// "16M colors", "65k colors" or "4096 colors"
+	colorsRe = regexp.MustCompile(`(?i)\b(\d+)\s*([KMB])?\s+colors`)
+	// "4 shades"
+	shadesRe = regexp.MustCompile(`(?i)\b(\d+)\s+shades`)
+)
+
+// ParseDisplayType decomposes a display_type string such as
+// "IPS LCD capacitive touchscreen, 16M colors" into its panel technology,
+// touch kind and color count. If none of them are found it returns nil.
+func ParseDisplayType(typeStr string) *DisplayType {
+	var display DisplayType
+	found := false
+	lower := strings.ToLower(typeStr)
+
+	// the first word naming a panel wins, so "LTPS IPS LCD" is an IPS LCD
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return (r < 'a' || r > 'z') && r != '-'
+	})
+	for _, word := range words {
+		word = word[strings.LastIndex(word, "-")+1:]
+		if panel, ok := panelNames[word]; ok {
+			display.Panel = panel
+			found = true
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(lower, "capacitive"):
+		display.Touch = TouchCapacitive
+		found = true
+	case strings.Contains(lower, "resistive"):
+		display.Touch = TouchResistive
+		found = true
+	case strings.Contains(lower, "touchscreen"):
+		display.Touch = TouchUnspecified
+		found = true
+	}
+
+	if match := colorsRe.FindStringSubmatch(typeStr); match != nil {
+		colors, err := strconv.Atoi(match[1])
+		if err == nil {
+			switch strings.ToUpper(match[2]) {
+			case "K":
+				colors *= 1000
+			case "M":
+				colors *= 1000000
+			case "B":
+				colors *= 1000000000
+			}
+			display.Colors = colors
+			found = true
+		}
+	}
+
+	if match := shadesRe.FindStringSubmatch(typeStr); match != nil {
+		display.Shades, _ = strconv.Atoi(match[1])
+		display.Panel = PanelMonochrome
+		found = true
+	}
+
+	if !found {
+		return nil
+	}
+	return &display
+}
+
+// String implements the Stringer interface for DisplayType, using the same
+// layout as the dataset.
+func (d DisplayType) String() string {
+	var parts []string
+	var technology []string
+	if d.Panel != PanelUnknown {
+		technology = append(technology, d.Panel.String())
+	}
+	switch d.Touch {
+	case TouchCapacitive, TouchResistive:
+		technology = append(technology, d.Touch.String(), "touchscreen")
+	case TouchUnspecified:
+		technology = append(technology, "touchscreen")
+	}
+	if len(technology) > 0 {
+		parts = append(parts, strings.Join(technology, " "))
+	}
+	if d.Colors > 0 {
+		parts = append(parts, formatColors(d.Colors)+" colors")
+	}
+	if d.Shades > 0 {
+		parts = append(parts, fmt.Sprintf("%d shades", d.Shades))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatColors writes a color count the way the dataset does, such as
+// "16M" for 16000000.
+func formatColors(colors int) string {
+	switch {
+	case colors >= 1000000000 && colors%1000000000 == 0:
+		return fmt.Sprintf("%dB", colors/1000000000)
+	case colors >= 1000000 && colors%1000000 == 0:
+		return fmt.Sprintf("%dM", colors/1000000)
+	case colors >= 1000 && colors%1000 == 0:
+		return fmt.Sprintf("%dK", colors/1000)
+	default:
+		return strconv.Itoa(colors)
+	}
+}
@@ -0,0 +1,53 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDisplayType(t *testing.T) {
+	tests := []struct {
+		input string
+		want  *DisplayType
+	}{
+		{"IPS LCD capacitive touchscreen, 16M colors", &DisplayType{Panel: PanelIPSLCD, Touch: TouchCapacitive, Colors: 16000000}},
+		{"LTPS IPS LCD capacitive touchscreen, 16M colors", &DisplayType{Panel: PanelIPSLCD, Touch: TouchCapacitive, Colors: 16000000}},
+		{"Super AMOLED capacitive touchscreen, 16M colors", &DisplayType{Panel: PanelAMOLED, Touch: TouchCapacitive, Colors: 16000000}},
+		{"Foldable P-OLED capacitive touchscreen, 16M colors", &DisplayType{Panel: PanelOLED, Touch: TouchCapacitive, Colors: 16000000}},
+		{"OLED capacitive touchscreen, 1B colors", &DisplayType{Panel: PanelOLED, Touch: TouchCapacitive, Colors: 1000000000}},
+		{"TFT resistive touchscreen, 65k colors", &DisplayType{Panel: PanelTFT, Touch: TouchResistive, Colors: 65000}},
+		{"TFT touchscreen", &DisplayType{Panel: PanelTFT, Touch: TouchUnspecified}},
+		{"CSTN, 4096 colors", &DisplayType{Panel: PanelSTN, Colors: 4096}},
+		{"TFD, 16M colors", &DisplayType{Panel: PanelTFD, Colors: 16000000}},
+		{"Monochrome graphic", &DisplayType{Panel: PanelMonochrome}},
+		{"Grayscale graphic, 4 shades", &DisplayType{Panel: PanelMonochrome, Shades: 4}},
+		{"65K colors", &DisplayType{Colors: 65000}},
+		{"V1", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		got := ParseDisplayType(test.input)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseDisplayType(%q) = %+v, want %+v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestDisplayTypeString(t *testing.T) {
+	tests := []struct {
+		display DisplayType
+		want    string
+	}{
+		{DisplayType{Panel: PanelIPSLCD, Touch: TouchCapacitive, Colors: 16000000}, "IPS LCD capacitive touchscreen, 16M colors"},
+		{DisplayType{Panel: PanelSTN, Colors: 4096}, "STN, 4096 colors"},
+		{DisplayType{}, "-"},
+	}
+
+	for _, test := range tests {
+		if got := test.display.String(); got != test.want {
+			t.Errorf("%+v.String() = %q, want %q", test.display, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package phonedb
+
+import (
+	"math"
+	"sort"
+)
+
+// Distribution describes how the known values of a numeric field are
+// spread. Percentiles are interpolated between the two nearest values, so
+// the median of 1, 2, 3 and 4 is 2.5.
+type Distribution struct {
+	// number of phones the field is known for
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean"`
+	// population standard deviation
+	StdDev float64 `json:"stddev"`
+	Min    float64 `json:"min"`
+	P5     float64 `json:"p5"`
+	P25    float64 `json:"p25"`
+	Median float64 `json:"median"`
+	P75    float64 `json:"p75"`
+	P95    float64 `json:"p95"`
+	Max    float64 `json:"max"`
+	// interquartile range, P75 - P25
+	IQR float64 `json:"iqr"`
+}
+
+// describe returns the distribution of values, or the zero Distribution if
+// there are none. values is not modified.
+func describe(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	d := Distribution{
+		Count:  len(sorted),
+		Mean:   mean(sorted),
+		Min:    sorted[0],
+		P5:     percentile(sorted, 5),
+		P25:    percentile(sorted, 25),
+		Median: percentile(sorted, 50),
+		P75:    percentile(sorted, 75),
+		P95:    percentile(sorted, 95),
+		Max:    sorted[len(sorted)-1],
+	}
+	d.StdDev = stdDev(sorted, d.Mean)
+	d.IQR = d.P75 - d.P25
+	return d
+}
+
+// percentile returns the p-th percentile of sorted, which must not be
+// empty, interpolating linearly between the two nearest values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// stdDev returns the population standard deviation of values around their
+// mean, or 0 if there are none.
+func stdDev(values []float64, mean float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(squares / float64(len(values)))
+}
+
+// Describe describes the distribution of metric in each group, excluding
+// phones for which it is unknown.
+func Describe(metric Metric) Aggregate[Distribution] {
+	return reduceMetric(metric, describe)
+}
+
+// CountKnown counts the phones of each group for which metric is known.
+func CountKnown(metric Metric) Aggregate[int] {
+	return Aggregate[int]{Known: metric.known, Reduce: func(cells []*Cell) int { return len(cells) }}
+}
+
+// StdDev returns the population standard deviation of metric in each
+// group, excluding phones for which it is unknown.
+func StdDev(metric Metric) Aggregate[float64] {
+	return reduceMetric(metric, func(values []float64) float64 { return stdDev(values, mean(values)) })
+}
+
+// Percentile returns the p-th percentile of metric in each group, such as
+// the 95th for p = 95, excluding phones for which it is unknown.
+func Percentile(metric Metric, p float64) Aggregate[float64] {
+	return reduceMetric(metric, func(values []float64) float64 {
+		sorted := append([]float64(nil), values...)
+		sort.Float64s(sorted)
+		return percentile(sorted, p)
+	})
+}
+
+// IQR returns the interquartile range of metric in each group, excluding
+// phones for which it is unknown.
+func IQR(metric Metric) Aggregate[float64] {
+	return reduceMetric(metric, func(values []float64) float64 { return describe(values).IQR })
+}
+
+// Describe describes the distribution of metric over the catalog. Phones
+// for which it is unknown are excluded.
+func (c *Catalog) Describe(metric Metric) Result[Distribution] {
+	cells, excluded := c.partition(metric.known)
+
+	values := make([]float64, len(cells))
+	for i, cell := range cells {
+		values[i] = metric.value(cell)
+	}
+	return Result[Distribution]{Value: describe(values), Included: len(cells), Excluded: excluded}
+}
+
+// DistributionFields are the numeric fields Summary describes, in the order
+// of the cells.csv columns they are read from.
+var DistributionFields = []string{
+	"launch_announced", "launch_released",
+	"body_height", "body_width", "body_depth", "body_weight", "body_sim_slots",
+	"display_colors", "display_size", "display_ppi",
+	"sensor_count",
+}
+
+// FieldDistribution is the distribution of one numeric field over a
+// catalog.
+type FieldDistribution struct {
+	Field        string               `json:"field"`
+	Distribution Result[Distribution] `json:"distribution"`
+}
+
+// DescribeFields describes the distribution of each of the
+// DistributionFields over the catalog, in the same order.
+func (c *Catalog) DescribeFields() []FieldDistribution {
+	distributions := make([]FieldDistribution, len(DistributionFields))
+	for i, name := range DistributionFields {
+		// every name is a numeric field, which TestDescribeFields checks
+		metric, _ := FieldMetric(name)
+		distributions[i] = FieldDistribution{Field: name, Distribution: c.Describe(metric)}
+	}
+	return distributions
+}
@@ -0,0 +1,68 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   Distribution
+	}{
+		{nil, Distribution{}},
+		{[]float64{7}, Distribution{Count: 1, Mean: 7, Min: 7, P5: 7, P25: 7, Median: 7, P75: 7, P95: 7, Max: 7}},
+		// 1 to 21: every percentile that is a multiple of 5 is a value
+		{[]float64{21, 20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+			Distribution{Count: 21, Mean: 11, StdDev: 6.0553007081949835, Min: 1, P5: 2, P25: 6, Median: 11, P75: 16, P95: 20, Max: 21, IQR: 10}},
+		{[]float64{1, 2, 3, 4},
+			Distribution{Count: 4, Mean: 2.5, StdDev: 1.118033988749895, Min: 1, P5: 1.15, P25: 1.75, Median: 2.5, P75: 3.25, P95: 3.8499999999999996, Max: 4, IQR: 1.5}},
+	}
+	for _, tt := range tests {
+		if got := describe(tt.values); got != tt.want {
+			t.Errorf("describe(%v) = %+v; want %+v", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeByGroup(t *testing.T) {
+	cells := groupCatalog()
+
+	overall := cells.Describe(weight)
+	if overall.Value.Count != 4 || overall.Value.Median != 155 || overall.Included != 4 || overall.Excluded != 2 {
+		t.Errorf("cells.Describe(weight) = %+v; want 4 weights with a median of 155", overall)
+	}
+
+	got := GroupBy(cells, ByOEM, Describe(weight)).Value
+	if len(got) != 2 || got["Google"].Median != 162 || got["Google"].IQR != 22.5 || got["Nokia"].Count != 1 {
+		t.Errorf("GroupBy(ByOEM, Describe(weight)) = %+v; want Google and Nokia", got)
+	}
+
+	aggregates := []struct {
+		name string
+		agg  Aggregate[float64]
+		want map[string]float64
+	}{
+		{"Percentile(95)", Percentile(weight, 95), map[string]float64{"Google": 189.9, "Nokia": 133}},
+		{"IQR", IQR(weight), map[string]float64{"Google": 22.5, "Nokia": 0}},
+		{"StdDev", StdDev(weight), map[string]float64{"Google": got["Google"].StdDev, "Nokia": 0}},
+	}
+	for _, a := range aggregates {
+		if got := GroupBy(cells, ByOEM, a.agg).Value; !reflect.DeepEqual(got, a.want) {
+			t.Errorf("GroupBy(ByOEM, %s) = %v; want %v", a.name, got, a.want)
+		}
+	}
+
+	known := GroupBy(cells, ByOEM, CountKnown(weight)).Value
+	if want := map[string]int{"Google": 3, "Nokia": 1}; !reflect.DeepEqual(known, want) {
+		t.Errorf("GroupBy(ByOEM, CountKnown(weight)) = %v; want %v", known, want)
+	}
+}
+
+func TestDescribeFields(t *testing.T) {
+	for _, name := range DistributionFields {
+		if _, err := FieldMetric(name); err != nil {
+			t.Errorf("FieldMetric(%q) error = %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package phonedb
+
+import (
+	"math"
+)
+
+// SchemaVersion is the version of the JSON documents built by this package.
+// It is raised whenever a field is removed, renamed or changes meaning, and
+// kept when fields are only added, so consumers should ignore fields they do
+// not know.
+const SchemaVersion = 1
+
+// Document is the envelope of every JSON document: the schema version, the
+// kind of report and the report itself.
+type Document struct {
+	SchemaVersion int `json:"schema_version"`
+	// kind of report in Data: "stats" for a Summary, "phones" for a list of
+	// PhoneDocument, "phone" for a single PhoneDocument, "quality" for a
+	// QualityDocument, "diff" for a list of ChangeDocument and "groups" for a
+	// GroupsDocument
+	Kind string `json:"kind"`
+	// file the report was computed from
+	Source string `json:"source,omitempty"`
+	Data   any    `json:"data"`
+}
+
+// NewDocument wraps data in a Document of the current SchemaVersion.
+func NewDocument(kind, source string, data any) Document {
+	return Document{SchemaVersion: SchemaVersion, Kind: kind, Source: source, Data: data}
+}
+
+// Summary holds the statistics of a whole catalog, as printed by "cell
+// stats".
+type Summary struct {
+	// number of phones in the catalog
+	Phones              int             `json:"phones"`
+	AverageWeight       Result[float64] `json:"average_weight_g"`
+	AverageDisplaySize  Result[float64] `json:"average_display_size_in"`
+	AverageThickness    Result[float64] `json:"average_thickness_mm"`
+	UniqueOS            Result[int]     `json:"unique_os"`
+	UniqueOSFamilies    Result[int]     `json:"unique_os_families"`
+	PhonesWithOneSensor Result[int]     `json:"phones_with_one_sensor"`
+	// heaviest and lightest phones, null if no weight is known
+	Heaviest *PhoneWeight `json:"heaviest"`
+	Lightest *PhoneWeight `json:"lightest"`
+	// OEM whose phones weigh the most on average
+	HeaviestOEM string `json:"highest_average_weight_oem"`
+	// phones announced in each year, in year order
+	AnnouncementsByYear Result[[]YearCount] `json:"announcements_by_year"`
+	// year of the 2000s with the most announcements, 0 if there were none
+	MostLaunchesIn2000s uint `json:"most_launches_in_2000s"`
+	// first year dual SIM phones outnumbered single SIM phones, null if
+	// they never did
+	DualSimOvertakeYear *uint `json:"dual_sim_overtake_year"`
+	// phone count, latest model and launch lag of every OEM, ordered by
+	// name; use SortOEMs for another order
+	OEMs []OEMSummary `json:"oems"`
+	// announce-to-release lag of the phones announced in each year, in
+	// year order
+	LagByYear Result[[]YearLag] `json:"lag_days_by_year"`
+	// phones announced and released in different years, in Phones order
+	AnnouncedReleasedMismatch Result[[]PhoneDetails] `json:"announced_released_different_years"`
+	// distribution of each of the DistributionFields
+	Distributions []FieldDistribution `json:"distributions"`
+}
+
+// PhoneWeight is a phone together with its weight.
+type PhoneWeight struct {
+	OEM    string  `json:"oem"`
+	Model  string  `json:"model"`
+	Weight float64 `json:"weight_g"`
+}
+
+// YearCount is the number of phones announced in a year.
+type YearCount struct {
+	Year  uint `json:"year"`
+	Count int  `json:"count"`
+}
+
+// YearLag is the announce-to-release lag of the phones announced in a
+// year.
+type YearLag struct {
+	Year uint     `json:"year"`
+	Lag  LagStats `json:"lag_days"`
+}
+
+// OEMSummary describes the phones of one OEM.
+type OEMSummary struct {
+	OEM         string `json:"oem"`
+	Phones      int    `json:"phones"`
+	LatestModel string `json:"latest_model"`
+	// announcement year of the latest model, 0 if it is unknown
+	LatestYear uint `json:"latest_year"`
+	// announce-to-release lag in days, null if no phone of the OEM has one
+	Lag *LagStats `json:"lag_days"`
+}
+
+// Summary computes the statistics of the catalog.
+func (c *Catalog) Summary() Summary {
+	s := Summary{
+		Phones:              c.Len(),
+		AverageWeight:       c.AverageWeight(),
+		AverageDisplaySize:  c.AverageDisplaySize(),
+		AverageThickness:    c.AverageThickness(),
+		UniqueOS:            c.CountUniqueOS(),
+		UniqueOSFamilies:    c.CountUniqueOSFamilies(),
+		PhonesWithOneSensor: c.CountPhonesWithOneSensor(),
+		HeaviestOEM:         c.FindOEMWithHighestAverageWeight(),
+	}
+
+	extremes := c.FindHeaviestAndLightestPhones().Value
+	if extremes.Heaviest != nil {
+		s.Heaviest = &PhoneWeight{extremes.Heaviest.oem, extremes.Heaviest.model, extremes.Heaviest.bodyWeight.Value()}
+		s.Lightest = &PhoneWeight{extremes.Lightest.oem, extremes.Lightest.model, extremes.Lightest.bodyWeight.Value()}
+	}
+
+	byYear := c.CountPhonesByYear()
+	years := make([]YearCount, len(byYear.Value.Years))
+	for i, year := range byYear.Value.Years {
+		years[i] = YearCount{Year: year, Count: byYear.Value.Counts[year]}
+	}
+	s.AnnouncementsByYear = Result[[]YearCount]{Value: years, Included: byYear.Included, Excluded: byYear.Excluded}
+	s.MostLaunchesIn2000s = FindMostLaunchesIn2000s(byYear.Value)
+	if year, ok := c.FindDualSimOvertakeYear(); ok {
+		s.DualSimOvertakeYear = &year
+	}
+
+	counts := c.CountPhonesByOEM()
+	latest := c.FindLatestPhoneByOEM()
+	lags := c.LagByOEM().Value
+	s.OEMs = make([]OEMSummary, 0, len(counts))
+	for oem, count := range counts {
+		summary := OEMSummary{OEM: oem, Phones: count, LatestModel: latest[oem].model}
+		if year, ok := latest[oem].LaunchAnnounced().Get(); ok {
+			summary.LatestYear = year
+		}
+		if lag, ok := lags[oem]; ok {
+			summary.Lag = &lag
+		}
+		s.OEMs = append(s.OEMs, summary)
+	}
+	SortOEMs(s.OEMs, SortByName)
+
+	lagByYear := c.LagByYear()
+	yearLags := make([]YearLag, 0, len(lagByYear.Value))
+	for _, year := range SortedKeys(lagByYear.Value) {
+		yearLags = append(yearLags, YearLag{Year: year, Lag: lagByYear.Value[year]})
+	}
+	s.LagByYear = Result[[]YearLag]{Value: yearLags, Included: lagByYear.Included, Excluded: lagByYear.Excluded}
+
+	s.AnnouncedReleasedMismatch = c.FindPhonesAnnouncedAndReleasedDifferentYears()
+	if s.AnnouncedReleasedMismatch.Value == nil {
+		s.AnnouncedReleasedMismatch.Value = []PhoneDetails{}
+	}
+	s.Distributions = c.DescribeFields()
+	return s
+}
+
+// PhoneDocument is the JSON form of a phone.
+type PhoneDocument struct {
+	OEM   string `json:"oem"`
+	Model string `json:"model"`
+	// line the phone was loaded from, omitted if it was not loaded from a
+	// file
+	Line int `json:"line,omitempty"`
+	// the other cells.csv columns written as Record writes them; unknown
+	// values are left out
+	Fields map[string]string `json:"fields"`
+}
+
+// Document returns the JSON form of the phone.
+func (c *Cell) Document() PhoneDocument {
+	return PhoneDocument{OEM: c.oem, Model: c.model, Line: c.line, Fields: recordFields(c.Record())}
+}
+
+// recordFields maps the columns of a Record other than oem and model to
+// their values, leaving out empty ones.
+func recordFields(record []string) map[string]string {
+	fields := make(map[string]string)
+	for i, name := range RequiredColumns {
+		if name != ColumnOEM && name != ColumnModel && record[i] != "" {
+			fields[name] = record[i]
+		}
+	}
+	return fields
+}
+
+// PhoneDocuments returns the JSON form of each phone, in the same order.
+func PhoneDocuments(phones []*Cell) []PhoneDocument {
+	docs := make([]PhoneDocument, len(phones))
+	for i, cell := range phones {
+		docs[i] = cell.Document()
+	}
+	return docs
+}
+
+// GroupsDocument is the JSON form of aggregates over groups of phones, as
+// printed by "cell group".
+type GroupsDocument struct {
+	// fields the phones were grouped by
+	By         []string            `json:"by"`
+	Aggregates []AggregateDocument `json:"aggregates"`
+	// groups ordered by their key, as SortFieldValues orders them
+	Groups []GroupDocument `json:"groups"`
+}
+
+// AggregateDocument describes one aggregate of a GroupsDocument.
+type AggregateDocument struct {
+	// aggregate as given, such as "count" or "mean:body_weight"
+	Name     string `json:"name"`
+	Included int    `json:"included"`
+	Excluded int    `json:"excluded"`
+}
+
+// GroupDocument is the JSON form of one group of phones.
+type GroupDocument struct {
+	// value of each field in By
+	Key []string `json:"key"`
+	// value of each aggregate by name, null if no phone of the group has
+	// the field it needs
+	Values map[string]any `json:"values"`
+}
+
+// ChangeDocument is the JSON form of a Change.
+type ChangeDocument struct {
+	Key string `json:"key"`
+	// "added", "removed" or "changed"
+	Change string `json:"change"`
+	// columns whose values differ, empty unless the phone was changed
+	Columns []string `json:"columns"`
+	// fields of the phone before and after the change, as in PhoneDocument;
+	// null for an added or a removed phone respectively
+	Before map[string]string `json:"before"`
+	After  map[string]string `json:"after"`
+}
+
+// Document returns the JSON form of the change.
+func (c Change) Document() ChangeDocument {
+	doc := ChangeDocument{Key: c.Key, Change: "changed", Columns: append([]string{}, c.Columns...)}
+	if c.Before == nil {
+		doc.Change = "added"
+	} else {
+		doc.Before = recordFields(c.Before.Record())
+	}
+	if c.After == nil {
+		doc.Change = "removed"
+	} else {
+		doc.After = recordFields(c.After.Record())
+	}
+	return doc
+}
+
+// QualityDocument is the JSON form of a QualityReport, together with the
+// outcome of the checks run against it.
+type QualityDocument struct {
+	Rows        int                     `json:"rows"`
+	Malformed   []RowErrorDocument      `json:"malformed"`
+	Repaired    []RowRepairDocument     `json:"repaired"`
+	Quarantined []RowErrorDocument      `json:"quarantined"`
+	Columns     []ColumnQualityDocument `json:"columns"`
+	OutOfRange  []OutOfRangeDocument    `json:"out_of_range"`
+	// violated thresholds, null if no thresholds were checked
+	ThresholdViolations []ThresholdViolation `json:"threshold_violations"`
+	// phones breaking a rule, null if no rules were checked
+	RuleViolations []RuleViolationDocument `json:"rule_violations"`
+}
+
+// RowErrorDocument is the JSON form of a RowError.
+type RowErrorDocument struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// RowRepairDocument is the JSON form of a RowRepair.
+type RowRepairDocument struct {
+	Line         int      `json:"line"`
+	Placeholders []string `json:"placeholders"`
+	Shift        int      `json:"shift"`
+}
+
+// ColumnQualityDocument is the JSON form of a ColumnQuality.
+type ColumnQualityDocument struct {
+	Column      string   `json:"column"`
+	Rows        int      `json:"rows"`
+	Filled      int      `json:"filled"`
+	Failed      int      `json:"failed"`
+	FillRate    float64  `json:"fill_rate"`
+	FailureRate float64  `json:"failure_rate"`
+	Examples    []string `json:"examples"`
+}
+
+// OutOfRangeDocument is the JSON form of an OutOfRange value.
+type OutOfRangeDocument struct {
+	Line   int     `json:"line"`
+	OEM    string  `json:"oem"`
+	Model  string  `json:"model"`
+	Column string  `json:"column"`
+	Raw    string  `json:"raw"`
+	Value  float64 `json:"value"`
+	Min    float64 `json:"min"`
+	// null if the range has no upper limit
+	Max  *float64 `json:"max"`
+	Unit string   `json:"unit,omitempty"`
+}
+
+// RuleViolationDocument is the JSON form of a RuleViolation.
+type RuleViolationDocument struct {
+	Line     int      `json:"line"`
+	OEM      string   `json:"oem"`
+	Model    string   `json:"model"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+}
+
+// Document returns the JSON form of the report, without any checks.
+func (r *QualityReport) Document() QualityDocument {
+	doc := QualityDocument{
+		Rows:        r.Rows,
+		Malformed:   make([]RowErrorDocument, len(r.Malformed)),
+		Repaired:    make([]RowRepairDocument, len(r.Repaired)),
+		Quarantined: make([]RowErrorDocument, len(r.Quarantined)),
+		Columns:     make([]ColumnQualityDocument, len(r.Columns)),
+		OutOfRange:  make([]OutOfRangeDocument, len(r.OutOfRange)),
+	}
+	for i, rowErr := range r.Malformed {
+		doc.Malformed[i] = RowErrorDocument{Line: rowErr.Line, Error: rowErr.Err.Error()}
+	}
+	for i, repair := range r.Repaired {
+		doc.Repaired[i] = RowRepairDocument{Line: repair.Line, Placeholders: append([]string{}, repair.Placeholders...), Shift: repair.Shift}
+	}
+	for i, rowErr := range r.Quarantined {
+		doc.Quarantined[i] = RowErrorDocument{Line: rowErr.Line, Error: rowErr.Err.Error()}
+	}
+	for i, q := range r.Columns {
+		doc.Columns[i] = ColumnQualityDocument{
+			Column:      q.Column,
+			Rows:        q.Rows,
+			Filled:      q.Filled,
+			Failed:      q.Failed,
+			FillRate:    q.FillRate(),
+			FailureRate: q.FailureRate(),
+			Examples:    append([]string{}, q.Examples...),
+		}
+	}
+	for i, o := range r.OutOfRange {
+		doc.OutOfRange[i] = OutOfRangeDocument{
+			Line:   o.Line,
+			OEM:    o.OEM,
+			Model:  o.Model,
+			Column: o.Range.Column,
+			Raw:    o.Raw,
+			Value:  o.Value,
+			Min:    o.Range.Min,
+			Unit:   o.Range.Unit,
+		}
+		if !math.IsInf(o.Range.Max, 1) {
+			highest := o.Range.Max
+			doc.OutOfRange[i].Max = &highest
+		}
+	}
+	return doc
+}
+
+// Document returns the JSON form of the violation.
+func (v RuleViolation) Document() RuleViolationDocument {
+	return RuleViolationDocument{Line: v.Cell.line, OEM: v.Cell.oem, Model: v.Cell.model, Rule: v.Rule.Name, Severity: v.Rule.Severity}
+}
@@ -0,0 +1,83 @@
+package phonedb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestSummaryDocument pins the JSON layout of the stats document. A change
+// to the expected output other than an added field needs a new
+// SchemaVersion.
+func TestSummaryDocument(t *testing.T) {
+	year := func(y uint) Date { return Date{Year: y, Precision: PrecisionYear} }
+	cells := &Catalog{cells: map[string]*Cell{
+		"Nokia-3310": {oem: "Nokia", model: "3310", bodyWeight: Some[float64](133),
+			launch: LaunchInfo{Announced: year(2000), Released: year(2000)}},
+		"Nokia-N97": {oem: "Nokia", model: "N97", bodyWeight: Some[float64](150),
+			launch: LaunchInfo{Announced: year(2008), Released: year(2009)}},
+		"Google-Pixel": {oem: "Google", model: "Pixel"},
+	}}
+
+	summary := cells.Summary()
+	if len(summary.Distributions) != len(DistributionFields) {
+		t.Fatalf("Summary().Distributions has %d fields; want %d", len(summary.Distributions), len(DistributionFields))
+	}
+	// every distribution has the same layout, so only the weight's is pinned
+	summary.Distributions = summary.Distributions[5:6]
+
+	got, err := json.Marshal(NewDocument("stats", "cells.csv", summary))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"schema_version":1,"kind":"stats","source":"cells.csv","data":{` +
+		`"phones":3,` +
+		`"average_weight_g":{"value":141.5,"included":2,"excluded":1},` +
+		`"average_display_size_in":{"value":0,"included":0,"excluded":3},` +
+		`"average_thickness_mm":{"value":0,"included":0,"excluded":3},` +
+		`"unique_os":{"value":0,"included":0,"excluded":3},` +
+		`"unique_os_families":{"value":0,"included":0,"excluded":3},` +
+		`"phones_with_one_sensor":{"value":0,"included":0,"excluded":3},` +
+		`"heaviest":{"oem":"Nokia","model":"N97","weight_g":150},` +
+		`"lightest":{"oem":"Nokia","model":"3310","weight_g":133},` +
+		`"highest_average_weight_oem":"Nokia",` +
+		`"announcements_by_year":{"value":[{"year":2000,"count":1},{"year":2008,"count":1}],"included":2,"excluded":1},` +
+		// 2000 and 2008 tie, the earlier year wins
+		`"most_launches_in_2000s":2000,` +
+		`"dual_sim_overtake_year":null,` +
+		`"oems":[{"oem":"Google","phones":1,"latest_model":"Pixel","latest_year":0,"lag_days":null},` +
+		`{"oem":"Nokia","phones":2,"latest_model":"N97","latest_year":2008,"lag_days":{"count":2,"mean":182.625,"median":182.625}}],` +
+		`"lag_days_by_year":{"value":[{"year":2000,"lag_days":{"count":1,"mean":0,"median":0}},` +
+		`{"year":2008,"lag_days":{"count":1,"mean":365.25,"median":365.25}}],"included":2,"excluded":1},` +
+		`"announced_released_different_years":{"value":[{"oem":"Nokia","model":"N97"}],"included":2,"excluded":1},` +
+		`"distributions":[{"field":"body_weight","distribution":{"value":{"count":2,"mean":141.5,"stddev":8.5,"min":133,` +
+		`"p5":133.85,"p25":137.25,"median":141.5,"p75":145.75,"p95":149.15,"max":150,"iqr":8.5},"included":2,"excluded":1}}]}}`
+	if string(got) != want {
+		t.Errorf("json.Marshal(Summary()) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestChangeDocument(t *testing.T) {
+	before := &Cell{oem: "Sony", model: "Xperia 1", displaySize: Some(6.5)}
+	after := &Cell{oem: "Sony", model: "Xperia 1", displaySize: Some(6.1), bodyWeight: Some[float64](178)}
+
+	tests := []struct {
+		change Change
+		want   string
+	}{
+		{Change{Key: "Sony-Xperia 1", After: after},
+			`{"key":"Sony-Xperia 1","change":"added","columns":[],"before":null,"after":{"body_weight":"178 g","display_size":"6.1 inches"}}`},
+		{Change{Key: "Sony-Xperia 1", Before: before},
+			`{"key":"Sony-Xperia 1","change":"removed","columns":[],"before":{"display_size":"6.5 inches"},"after":null}`},
+		{Change{Key: "Sony-Xperia 1", Before: before, After: after, Columns: []string{ColumnBodyWeight, ColumnDisplaySize}},
+			`{"key":"Sony-Xperia 1","change":"changed","columns":["body_weight","display_size"],"before":{"display_size":"6.5 inches"},"after":{"body_weight":"178 g","display_size":"6.1 inches"}}`},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.change.Document())
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("json.Marshal(%v.Document()) = %s; want %s", tt.change, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package phonedb
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// columnFormatters write a field of a Cell the way cells.csv does, so that
+// Header.ParseRecord reads it back into the same value. Unknown values are
+// written as empty strings.
+var columnFormatters = map[string]func(*Cell) string{
+	ColumnOEM:   func(c *Cell) string { return c.oem },
+	ColumnModel: func(c *Cell) string { return c.model },
+	ColumnLaunchAnnounced: func(c *Cell) string {
+		if !c.launch.Announced.Known() {
+			return ""
+		}
+		return c.launch.Announced.String()
+	},
+	// the release date is written after the status, as in "Available.
+	// Released 2019, October 24"
+	ColumnLaunchStatus: func(c *Cell) string {
+		if c.launch.Status == StatusUnknown && !c.launch.Released.Known() {
+			return ""
+		}
+		return c.launch.String()
+	},
+	ColumnBodyDimensions: func(c *Cell) string { return formatKnown(c.bodyDimensions) },
+	ColumnBodyWeight: func(c *Cell) string {
+		if w, ok := c.bodyWeight.Get(); ok {
+			return strconv.FormatFloat(w, 'f', -1, 64) + " g"
+		}
+		return ""
+	},
+	ColumnBodySim:     func(c *Cell) string { return formatKnown(c.bodySim) },
+	ColumnDisplayType: func(c *Cell) string { return formatKnown(c.displayType) },
+	ColumnDisplaySize: func(c *Cell) string {
+		if size, ok := c.displaySize.Get(); ok {
+			return strconv.FormatFloat(size, 'f', -1, 64) + " inches"
+		}
+		return ""
+	},
+	ColumnDisplayResolution: func(c *Cell) string { return formatKnown(c.displayResolution) },
+	ColumnFeaturesSensors:   func(c *Cell) string { return formatKnown(c.featuresSensors) },
+	ColumnPlatformOS:        func(c *Cell) string { return formatKnown(c.platformOS) },
+}
+
+// formatKnown returns the text of a known value, or "" if it is unknown.
+func formatKnown[T fmt.Stringer](o Optional[T]) string {
+	if !o.Known() {
+		return ""
+	}
+	return o.Value().String()
+}
+
+// Record returns the fields of the phone in cells.csv layout, in
+// RequiredColumns order.
+func (c *Cell) Record() []string {
+	record := make([]string, len(RequiredColumns))
+	for i, name := range RequiredColumns {
+		record[i] = columnFormatters[name](c)
+	}
+	return record
+}
+
+// WriteCSV writes the phones in the catalog to w in cells.csv format, with a
+// header line of the RequiredColumns followed by one row per phone in Phones
+// order. The output can be read back with Load.
+func (c *Catalog) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(RequiredColumns); err != nil {
+		return err
+	}
+	for _, cell := range c.Phones() {
+		if err := writer.Write(cell.Record()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,40 @@
+package phonedb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteCSVReadsBack(t *testing.T) {
+	data := strings.Join(RequiredColumns, ",") + "\n" +
+		`Google,Pixel 4 XL,"2019, October 15","Available. Released 2019, October 22",160.4 x 75.1 x 8.2 mm (6.31 x 2.96 x 0.32 in),193 g (6.81 oz),"Nano-SIM, eSIM","OLED capacitive touchscreen, 16M colors","6.3 inches, 100.0 cm","1440 x 3040 pixels, 19:9 ratio (~537 ppi density)","Fingerprint (rear-mounted), accelerometer, gyro",Android 10` + "\n" +
+		`Samsung,E410,"2004, Q1",Discontinued,73 x 53 x 23 mm,80 g,Mini-SIM,"UFB, 65K colors",V1,"128 x 128 pixels, 1:1 ratio",V1,` + "\n"
+
+	catalog, _, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := catalog.WriteCSV(&out); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	again, _, err := Load(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("Load(WriteCSV()) error = %v", err)
+	}
+
+	if changes := Diff(catalog, again); len(changes) > 0 {
+		t.Errorf("Diff(catalog, Load(WriteCSV())) = %v; want no changes", changes)
+	}
+	for key, cell := range catalog.Cells() {
+		if !sameFields(cell, again.Cells()[key]) {
+			t.Errorf("Load(WriteCSV()) %s = %v; want %v", key, again.Cells()[key], cell)
+		}
+	}
+
+	want := []string{"Samsung", "E410", "2004, Q1", "Discontinued", "73 x 53 x 23 mm", "80 g", "Single SIM (Mini-SIM)", "Other, 65K colors", "", "128 x 128 pixels, 1:1 ratio", "", ""}
+	if got := catalog.Get("Samsung", "E410").Record(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Record() = %q; want %q", got, want)
+	}
+}
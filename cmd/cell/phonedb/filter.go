@@ -0,0 +1,453 @@
+package phonedb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fieldAliases are shorter names filter expressions accept for some of the
+// fields rules can refer to.
+var fieldAliases = map[string]string{
+	"year":     "launch_announced",
+	"decade":   "launch_decade",
+	"released": "launch_released",
+	"status":   "launch_status",
+	"weight":   "body_weight",
+	"size":     "display_size",
+	"panel":    "display_panel",
+	"ppi":      "display_ppi",
+	"os":       "platform_os",
+}
+
+// Filter selects phones with an expression such as
+//
+//	oem = Samsung and year >= 2018 and display_size > 6
+//
+// Comparisons name a field on the left, using the field names of rules or
+// one of the aliases year, released, status, weight, size, panel, ppi and
+// os, and take one of these forms:
+//
+//	field = value, field != value, field < value, ... field >= value
+//	field in (value, value, ...), field not in (...)
+//	field contains "text", field not contains "text"
+//	field matches "regexp", or field ~ "regexp"
+//	field is known, field is not known
+//
+// Values are numbers, quoted text, or bare words, which are taken as text
+// unless they name another field. Comparisons combine with and, or, not and
+// parentheses, and keywords and field names ignore case. As in rules, a
+// comparison with an unknown field is neither true nor false; a Filter
+// selects only the phones for which the whole expression is true. The zero
+// Filter selects every phone.
+type Filter struct {
+	expr string
+	cond *Condition
+}
+
+// ParseFilter parses a filter expression. An empty expression selects every
+// phone.
+func ParseFilter(expr string) (*Filter, error) {
+	f := &Filter{expr: strings.TrimSpace(expr)}
+	if f.expr == "" {
+		return f, nil
+	}
+	tokens, err := lexFilter(f.expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok)
+	}
+	f.cond = &cond
+	return f, nil
+}
+
+// String returns the expression the filter was parsed from.
+func (f *Filter) String() string {
+	return f.expr
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for Filter.
+func (f *Filter) MarshalText() ([]byte, error) {
+	return []byte(f.expr), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for
+// Filter, so it can be used as a flag with flag.TextVar.
+func (f *Filter) UnmarshalText(text []byte) error {
+	parsed, err := ParseFilter(string(text))
+	if err != nil {
+		return err
+	}
+	*f = *parsed
+	return nil
+}
+
+// Match reports whether the expression is true for cell.
+func (f *Filter) Match(cell *Cell) bool {
+	return f.cond == nil || f.cond.eval(cell) == truthTrue
+}
+
+// Where returns a catalog of the phones f matches, stored under the same
+// keys, so every aggregate can run on a subset of the phones. The phones
+// are shared with c, not copied.
+func (c *Catalog) Where(f *Filter) *Catalog {
+	subset := NewCatalog()
+	for key, cell := range c.cells {
+		if f.Match(cell) {
+			subset.cells[key] = cell
+		}
+	}
+	return subset
+}
+
+// tokenKind is the kind of a token of a filter expression.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	// a field name, keyword or bare word
+	tokWord
+	tokNumber
+	// quoted text, with the quotes removed
+	tokText
+	// a comparison operator such as "<="
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+// token is a token of a filter expression.
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	// offset of the token in the expression
+	pos int
+}
+
+// String implements the Stringer interface for token, describing it for
+// error messages.
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokText:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// isKeyword reports whether t is the given keyword, ignoring case.
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, keyword)
+}
+
+// filterKeywords cannot be used as bare words.
+var filterKeywords = map[string]bool{
+	"and": true, "or": true, "not": true, "in": true, "contains": true,
+	"matches": true, "is": true, "known": true,
+}
+
+// lexFilter splits a filter expression into tokens, ending with tokEOF.
+func lexFilter(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		r, size := utf8.DecodeRuneInString(expr[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		case strings.ContainsRune("=!<>~", r):
+			op := expr[i : i+1]
+			if i+1 < len(expr) && expr[i+1] == '=' && r != '~' {
+				op = expr[i : i+2]
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("parse filter: column %d: \"!\" must be followed by \"=\"", i+1)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(expr) && expr[end] != byte(r) {
+				if expr[end] == '\\' && r == '"' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("parse filter: column %d: unterminated text", i+1)
+			}
+			value := expr[i+1 : end]
+			if r == '"' {
+				unquoted, err := strconv.Unquote(expr[i : end+1])
+				if err != nil {
+					return nil, fmt.Errorf("parse filter: column %d: %v", i+1, err)
+				}
+				value = unquoted
+			}
+			tokens = append(tokens, token{kind: tokText, text: value, pos: i})
+			i = end + 1
+		case r >= '0' && r <= '9' || r == '-' && i+1 < len(expr) && expr[i+1] >= '0' && expr[i+1] <= '9':
+			end := i + 1
+			for end < len(expr) && (expr[end] >= '0' && expr[end] <= '9' || expr[end] == '.') {
+				end++
+			}
+			n, err := strconv.ParseFloat(expr[i:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse filter: column %d: bad number %q", i+1, expr[i:end])
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: expr[i:end], num: n, pos: i})
+			i = end
+		default:
+			end := i
+			for end < len(expr) {
+				r, size := utf8.DecodeRuneInString(expr[end:])
+				if unicode.IsSpace(r) || strings.ContainsRune("()=!<>~,\"'", r) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, token{kind: tokWord, text: expr[i:end], pos: i})
+			i = end
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(expr)}), nil
+}
+
+// filterParser builds a Condition from the tokens of a filter expression by
+// recursive descent. "or" binds loosest, then "and", then "not".
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+// peek returns the next token without consuming it.
+func (p *filterParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the next token. The final tokEOF is never
+// consumed.
+func (p *filterParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// errorf returns an error pointing at tok.
+func (p *filterParser) errorf(tok token, format string, args ...any) error {
+	return fmt.Errorf("parse filter: column %d: %s", tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+// parseOr parses "a or b or ...".
+func (p *filterParser) parseOr() (Condition, error) {
+	return p.parseList("or", p.parseAnd, func(conds []Condition) Condition { return Condition{Any: conds} })
+}
+
+// parseAnd parses "a and b and ...".
+func (p *filterParser) parseAnd() (Condition, error) {
+	return p.parseList("and", p.parseNot, func(conds []Condition) Condition { return Condition{All: conds} })
+}
+
+// parseList parses operands joined by keyword, combining two or more of
+// them with join.
+func (p *filterParser) parseList(keyword string, operand func() (Condition, error), join func([]Condition) Condition) (Condition, error) {
+	first, err := operand()
+	if err != nil {
+		return Condition{}, err
+	}
+	conds := []Condition{first}
+	for p.peek().isKeyword(keyword) {
+		p.next()
+		cond, err := operand()
+		if err != nil {
+			return Condition{}, err
+		}
+		conds = append(conds, cond)
+	}
+	if len(conds) == 1 {
+		return first, nil
+	}
+	return join(conds), nil
+}
+
+// parseNot parses "not a", a parenthesized expression or a comparison.
+func (p *filterParser) parseNot() (Condition, error) {
+	tok := p.peek()
+	switch {
+	case tok.isKeyword("not"):
+		p.next()
+		cond, err := p.parseNot()
+		if err != nil {
+			return Condition{}, err
+		}
+		return Condition{Not: &cond}, nil
+	case tok.kind == tokLParen:
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return Condition{}, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return Condition{}, p.errorf(closing, "expected \")\", found %s", closing)
+		}
+		return cond, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses a comparison of a field.
+func (p *filterParser) parseComparison() (Condition, error) {
+	tok := p.next()
+	name, ok := p.field(tok)
+	if !ok {
+		return Condition{}, p.errorf(tok, "expected a field name, found %s", tok)
+	}
+	cond := Condition{Field: name}
+
+	// "not in" and "not contains" negate the comparison that follows
+	negate := false
+	opTok := p.next()
+	if opTok.isKeyword("not") {
+		negate = true
+		opTok = p.next()
+		if !opTok.isKeyword("in") && !opTok.isKeyword("contains") && !opTok.isKeyword("matches") {
+			return Condition{}, p.errorf(opTok, "expected in, contains or matches after not, found %s", opTok)
+		}
+	}
+
+	switch {
+	case opTok.kind == tokOp:
+		cond.Op = opTok.text
+		switch cond.Op {
+		case "=":
+			cond.Op = "=="
+		case "~":
+			cond.Op = "matches"
+		}
+		operand := p.next()
+		if other, ok := p.field(operand); ok && cond.Op != "matches" {
+			cond.Other = other
+		} else if cond.Value, ok = operandValue(name, operand); !ok {
+			return Condition{}, p.errorf(operand, "expected a value after %q, found %s", opTok.text, operand)
+		}
+	case opTok.isKeyword("contains"), opTok.isKeyword("matches"):
+		cond.Op = strings.ToLower(opTok.text)
+		operand := p.next()
+		value, ok := operandValue(name, operand)
+		if !ok {
+			return Condition{}, p.errorf(operand, "expected text after %s, found %s", cond.Op, operand)
+		}
+		cond.Value = value
+	case opTok.isKeyword("in"):
+		cond.Op = "in"
+		values, err := p.parseValueList(name)
+		if err != nil {
+			return Condition{}, err
+		}
+		cond.Value = values
+	case opTok.isKeyword("is"):
+		known := true
+		if p.peek().isKeyword("not") {
+			p.next()
+			known = false
+		}
+		if word := p.next(); !word.isKeyword("known") {
+			return Condition{}, p.errorf(word, "expected known after is, found %s", word)
+		}
+		cond.Known = &known
+	default:
+		return Condition{}, p.errorf(opTok, "expected a comparison after %s, found %s", tok, opTok)
+	}
+
+	if err := cond.validate(); err != nil {
+		return Condition{}, p.errorf(tok, "%v", err)
+	}
+	if negate {
+		return Condition{Not: &cond}, nil
+	}
+	return cond, nil
+}
+
+// parseValueList parses "(value, value, ...)" for the named field.
+func (p *filterParser) parseValueList(name string) ([]any, error) {
+	if open := p.next(); open.kind != tokLParen {
+		return nil, p.errorf(open, "expected \"(\" after in, found %s", open)
+	}
+	var values []any
+	for {
+		tok := p.next()
+		value, ok := operandValue(name, tok)
+		if !ok {
+			return nil, p.errorf(tok, "expected a value, found %s", tok)
+		}
+		values = append(values, value)
+		switch sep := p.next(); sep.kind {
+		case tokComma:
+		case tokRParen:
+			return values, nil
+		default:
+			return nil, p.errorf(sep, "expected \",\" or \")\", found %s", sep)
+		}
+	}
+}
+
+// field returns the canonical name of the field tok names, if it names one.
+func (p *filterParser) field(tok token) (string, bool) {
+	if tok.kind != tokWord {
+		return "", false
+	}
+	name, _, ok := lookupField(tok.text)
+	return name, ok
+}
+
+// lookupField returns the canonical name and the field named name, which
+// is either the name of one of the fields rules can refer to or one of its
+// fieldAliases, ignoring case.
+func lookupField(name string) (string, ruleField, bool) {
+	name = strings.ToLower(name)
+	if alias, ok := fieldAliases[name]; ok {
+		name = alias
+	}
+	field, ok := ruleFields[name]
+	return name, field, ok
+}
+
+// operandValue returns the value of a number, quoted text or bare word
+// token compared with the named field. Numbers compared with a text field
+// are taken as text, so that "model = 3310" works.
+func operandValue(name string, tok token) (any, bool) {
+	switch {
+	case tok.kind == tokNumber && !ruleFields[name].numeric:
+		return tok.text, true
+	case tok.kind == tokNumber:
+		return tok.num, true
+	case tok.kind == tokText:
+		return tok.text, true
+	case tok.kind == tokWord && !filterKeywords[strings.ToLower(tok.text)]:
+		return tok.text, true
+	}
+	return nil, false
+}
@@ -0,0 +1,121 @@
+package phonedb
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	year := func(y uint) LaunchInfo { return LaunchInfo{Announced: Date{Year: y, Precision: PrecisionYear}} }
+	cells := &Catalog{cells: map[string]*Cell{
+		"Samsung-Galaxy S10": {oem: "Samsung", model: "Galaxy S10", launch: year(2019), displaySize: Some(6.1),
+			featuresSensors: Some(Sensors{List: []Sensor{SensorFingerprint, SensorAccelerometer}})},
+		"Samsung-Galaxy S9": {oem: "Samsung", model: "Galaxy S9", launch: year(2018), displaySize: Some(5.8)},
+		"Samsung-Note 10+":  {oem: "Samsung", model: "Note 10+", launch: year(2019)},
+		"LG-G8 ThinQ":       {oem: "LG", model: "G8 ThinQ", launch: year(2019), displaySize: Some(6.1)},
+		"Nokia-3310":        {oem: "Nokia", model: "3310", launch: year(2000), bodyWeight: Some[float64](133)},
+	}}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"", []string{"3310", "G8 ThinQ", "Galaxy S10", "Galaxy S9", "Note 10+"}},
+		{"OEM = Samsung and year >= 2018 and display_size > 6", []string{"Galaxy S10"}},
+		{"oem = samsung and not size > 6", []string{"Galaxy S9"}},
+		{"size > 6 or year < 2001", []string{"3310", "G8 ThinQ", "Galaxy S10"}},
+		{"oem in (LG, Nokia)", []string{"3310", "G8 ThinQ"}},
+		{"oem not in ('LG', 'Nokia')", []string{"Galaxy S10", "Galaxy S9", "Note 10+"}},
+		{"model contains galaxy", []string{"Galaxy S10", "Galaxy S9"}},
+		{`model matches "^Galaxy S\\d$"`, []string{"Galaxy S9"}},
+		{"model ~ '^(?i)note'", []string{"Note 10+"}},
+		{"sensors contains fingerprint", []string{"Galaxy S10"}},
+		{"model = 3310", []string{"3310"}},
+		{"weight is known", []string{"3310"}},
+		{"size is not known and oem != Nokia", []string{"Note 10+"}},
+		{"(oem = LG or oem = Nokia) and (year = 2019)", []string{"G8 ThinQ"}},
+		{"year in (2018, 2000)", []string{"3310", "Galaxy S9"}},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr)
+		if err != nil {
+			t.Errorf("ParseFilter(%q) error = %v", tt.expr, err)
+			continue
+		}
+		var got []string
+		for _, cell := range cells.Where(f).Cells() {
+			got = append(got, cell.model)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("cells.Where(%q) = %q; want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+// TestFilterDecimals filters phones loaded from text, so a decimal in the
+// file must equal the same decimal in the expression.
+func TestFilterDecimals(t *testing.T) {
+	data := strings.Join(RequiredColumns, ",") + "\n" +
+		"Google,Pixel 4 XL,2019,Available,-,193 g,Nano-SIM,OLED,6.3 inches,-,Accelerometer,Android 10\n" +
+		"Nokia,2.3,2019,Available,-,183 g,Nano-SIM,IPS LCD,6.2 inches,-,Accelerometer,Android 9\n" +
+		"Sony,Xperia 10,2019,Available,-,162 g,Nano-SIM,IPS LCD,6.0 inches,-,Accelerometer,Android 9\n" +
+		"Nokia,225,2020,Available,-,119.4 g,Mini-SIM,TFT,2.4 inches,-,,\n"
+	cells, _, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"display_size = 6.3", []string{"Pixel 4 XL"}},
+		{"size != 6.3 and size >= 6", []string{"2.3", "Xperia 10"}},
+		{"size in (6.2, 6.3)", []string{"2.3", "Pixel 4 XL"}},
+		{"size <= 6.2", []string{"2.3", "225", "Xperia 10"}},
+		{"body_weight = 119.4", []string{"225"}},
+		{"weight < 119.5 and weight > 119.3", []string{"225"}},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr)
+		if err != nil {
+			t.Errorf("ParseFilter(%q) error = %v", tt.expr, err)
+			continue
+		}
+		var got []string
+		for _, cell := range cells.Where(f).Cells() {
+			got = append(got, cell.model)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("cells.Where(%q) = %q; want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"weight > heavy", `column 1: field "body_weight" is a number, not text`},
+		{"colour = red", `column 1: expected a field name, found "colour"`},
+		{"oem < Samsung", "column 1: text can only be compared with == or !="},
+		{"size > 6 and", "column 13: expected a field name, found end of expression"},
+		{"(size > 6", `column 10: expected ")", found end of expression`},
+		{"oem = 'LG", "column 7: unterminated text"},
+		{"oem in LG", `column 8: expected "(" after in, found "LG"`},
+		{"model matches '('", "column 1: error parsing regexp"},
+		{"size > 6 size < 7", `column 10: unexpected "size"`},
+		{"oem ! LG", `column 5: "!" must be followed by "="`},
+	}
+	for _, tt := range tests {
+		_, err := ParseFilter(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseFilter(%q) error = %v; want it to contain %q", tt.expr, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package phonedb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metric reads a number from a phone, reporting false if it is unknown.
+type Metric func(*Cell) (float64, bool)
+
+// Aggregate reduces the phones of a group to a single value, such as their
+// count or their average weight.
+type Aggregate[V any] struct {
+	// Known reports whether the aggregate can use a phone. Phones it
+	// reports false for are excluded from every group. A nil Known uses
+	// every phone.
+	Known func(*Cell) bool
+	// Reduce computes the value of a group from its phones, which are in
+	// Phones order. It is never called with an empty group.
+	Reduce func([]*Cell) V
+}
+
+// GroupBy splits the phones in the catalog into groups by the key returns
+// for them and reduces every group with agg. Phones for which key reports
+// false or agg cannot use are excluded, so a key only appears in the result
+// if at least one of its phones was included.
+//
+// For example, the average display size of every OEM in every year is
+//
+//	GroupBy(c, ByPair(ByOEM, ByYear), Mean(size))
+//
+// where size is a Metric reading the display size.
+func GroupBy[K comparable, V any](c *Catalog, key func(*Cell) (K, bool), agg Aggregate[V]) Result[map[K]V] {
+	cells, excluded := c.partition(func(cell *Cell) bool {
+		_, ok := key(cell)
+		return ok && (agg.Known == nil || agg.Known(cell))
+	})
+
+	groups := make(map[K][]*Cell)
+	for _, cell := range cells {
+		k, _ := key(cell)
+		groups[k] = append(groups[k], cell)
+	}
+
+	values := make(map[K]V, len(groups))
+	for k, group := range groups {
+		values[k] = agg.Reduce(group)
+	}
+	return Result[map[K]V]{Value: values, Included: len(cells), Excluded: excluded}
+}
+
+// Count counts the phones of each group.
+func Count() Aggregate[int] {
+	return Aggregate[int]{Reduce: func(cells []*Cell) int { return len(cells) }}
+}
+
+// Sum adds up metric over each group, excluding phones for which it is
+// unknown.
+func Sum(metric Metric) Aggregate[float64] {
+	return reduceMetric(metric, func(values []float64) float64 {
+		var total float64
+		for _, v := range values {
+			total += v
+		}
+		return total
+	})
+}
+
+// Mean averages metric over each group, excluding phones for which it is
+// unknown.
+func Mean(metric Metric) Aggregate[float64] {
+	return reduceMetric(metric, mean)
+}
+
+// Median returns the median of metric in each group, excluding phones for
+// which it is unknown.
+func Median(metric Metric) Aggregate[float64] {
+	return reduceMetric(metric, median)
+}
+
+// Min returns the smallest value of metric in each group, excluding phones
+// for which it is unknown.
+func Min(metric Metric) Aggregate[float64] {
+	return reduceMetric(metric, func(values []float64) float64 {
+		least := values[0]
+		for _, v := range values[1:] {
+			if v < least {
+				least = v
+			}
+		}
+		return least
+	})
+}
+
+// Max returns the largest value of metric in each group, excluding phones
+// for which it is unknown.
+func Max(metric Metric) Aggregate[float64] {
+	return reduceMetric(metric, func(values []float64) float64 {
+		greatest := values[0]
+		for _, v := range values[1:] {
+			if v > greatest {
+				greatest = v
+			}
+		}
+		return greatest
+	})
+}
+
+// ArgMax returns the phone of each group with the largest value of metric,
+// excluding phones for which it is unknown. Of several phones with the same
+// value, the first in Phones order is returned.
+func ArgMax(metric Metric) Aggregate[*Cell] {
+	return Aggregate[*Cell]{
+		Known: metric.known,
+		Reduce: func(cells []*Cell) *Cell {
+			found, greatest := cells[0], metric.value(cells[0])
+			for _, cell := range cells[1:] {
+				if v := metric.value(cell); v > greatest {
+					found, greatest = cell, v
+				}
+			}
+			return found
+		},
+	}
+}
+
+// reduceMetric returns an Aggregate applying reduce to the values of metric
+// in a group, excluding phones for which it is unknown.
+func reduceMetric[V any](metric Metric, reduce func([]float64) V) Aggregate[V] {
+	return Aggregate[V]{
+		Known: metric.known,
+		Reduce: func(cells []*Cell) V {
+			values := make([]float64, len(cells))
+			for i, cell := range cells {
+				values[i] = metric.value(cell)
+			}
+			return reduce(values)
+		},
+	}
+}
+
+// known reports whether the metric is known for the phone.
+func (m Metric) known(cell *Cell) bool {
+	_, ok := m(cell)
+	return ok
+}
+
+// value returns the metric of the phone, which must be known.
+func (m Metric) value(cell *Cell) float64 {
+	v, _ := m(cell)
+	return v
+}
+
+// ByOEM groups phones by OEM.
+func ByOEM(cell *Cell) (string, bool) {
+	return cell.oem, true
+}
+
+// ByYear groups phones by announcement year, excluding phones without one.
+func ByYear(cell *Cell) (uint, bool) {
+	return cell.launch.Announced.Year, hasYear(cell)
+}
+
+// ByDecade groups phones by the decade they were announced in, such as 2010
+// for 2010 to 2019, excluding phones without an announcement year.
+func ByDecade(cell *Cell) (uint, bool) {
+	year, ok := ByYear(cell)
+	return year - year%10, ok
+}
+
+// ByOSFamily groups phones by operating system family, such as "Android",
+// excluding phones without a known operating system.
+func ByOSFamily(cell *Cell) (string, bool) {
+	os, ok := cell.platformOS.Get()
+	return os.Family, ok
+}
+
+// Pair is a group key made of two keys, as built by ByPair.
+type Pair[A, B comparable] struct {
+	First  A
+	Second B
+}
+
+// ByPair groups phones by two keys at once, excluding phones for which
+// either key reports false.
+func ByPair[A, B comparable](first func(*Cell) (A, bool), second func(*Cell) (B, bool)) func(*Cell) (Pair[A, B], bool) {
+	return func(cell *Cell) (Pair[A, B], bool) {
+		a, ok := first(cell)
+		if !ok {
+			return Pair[A, B]{}, false
+		}
+		b, ok := second(cell)
+		return Pair[A, B]{a, b}, ok
+	}
+}
+
+// ordered is the types whose values can be compared with <.
+type ordered interface {
+	~int | ~int64 | ~uint | ~float32 | ~float64 | ~string
+}
+
+// SortedKeys returns the keys of m in increasing order.
+func SortedKeys[K ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// FieldMetric returns a Metric reading the named numeric field, which is one
+// of the fields rules can refer to or one of the shorter names filter
+// expressions accept, such as "weight".
+func FieldMetric(name string) (Metric, error) {
+	canonical, field, ok := lookupField(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", name)
+	}
+	if !field.numeric {
+		return nil, fmt.Errorf("field %q is not a number", canonical)
+	}
+	return func(cell *Cell) (float64, bool) {
+		v := field.get(cell)
+		return v.num, v.known
+	}, nil
+}
+
+// fieldSeparator separates the values of a FieldValues key. It cannot occur
+// in a field read from a CSV file.
+const fieldSeparator = "\x1f"
+
+// FieldValues is a group key holding the values of one or more fields, as
+// built by FieldKey.
+type FieldValues string
+
+// Values returns the value of each field, in the order the fields were
+// given to FieldKey. Numbers are written in their shortest form, as in
+// "2019" or "6.1".
+func (v FieldValues) Values() []string {
+	return strings.Split(string(v), fieldSeparator)
+}
+
+// FieldKey returns a key grouping phones by the values of the named fields,
+// which are the fields rules can refer to or the shorter names filter
+// expressions accept. Phones for which any of the fields is unknown are
+// excluded.
+func FieldKey(names ...string) (func(*Cell) (FieldValues, bool), error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no fields to group by")
+	}
+	fields := make([]ruleField, len(names))
+	for i, name := range names {
+		_, field, ok := lookupField(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		fields[i] = field
+	}
+	return func(cell *Cell) (FieldValues, bool) {
+		values := make([]string, len(fields))
+		for i, field := range fields {
+			v := field.get(cell)
+			if !v.known {
+				return "", false
+			}
+			values[i] = v.str
+			if field.numeric {
+				values[i] = strconv.FormatFloat(v.num, 'f', -1, 64)
+			}
+		}
+		return FieldValues(strings.Join(values, fieldSeparator)), true
+	}, nil
+}
+
+// SortFieldValues sorts keys in place by their first value, then their
+// second and so on. Numbers are compared as numbers, so that "9" comes
+// before "10", and come before text.
+func SortFieldValues(keys []FieldValues) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i].Values(), keys[j].Values()
+		for k := range a {
+			if a[k] == b[k] {
+				continue
+			}
+			x, errX := strconv.ParseFloat(a[k], 64)
+			y, errY := strconv.ParseFloat(b[k], 64)
+			switch {
+			case errX == nil && errY == nil && x != y:
+				return x < y
+			case (errX == nil) != (errY == nil):
+				return errX == nil
+			}
+			return a[k] < b[k]
+		}
+		return false
+	})
+}
@@ -0,0 +1,110 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func groupCatalog() *Catalog {
+	return &Catalog{cells: map[string]*Cell{
+		"Google-Pixel 3":     {oem: "Google", model: "Pixel 3", launch: announcedIn(2018), displaySize: Some(5.5), bodyWeight: Some[float64](148)},
+		"Google-Pixel 4":     {oem: "Google", model: "Pixel 4", launch: announcedIn(2019), displaySize: Some(5.7), bodyWeight: Some[float64](162)},
+		"Google-Pixel 4 XL":  {oem: "Google", model: "Pixel 4 XL", launch: announcedIn(2019), displaySize: Some(6.3), bodyWeight: Some[float64](193)},
+		"Samsung-Galaxy S10": {oem: "Samsung", model: "Galaxy S10", launch: announcedIn(2019), displaySize: Some(6.1)},
+		"Nokia-3310":         {oem: "Nokia", model: "3310", launch: announcedIn(2000), bodyWeight: Some[float64](133)},
+		"Nokia-Unknown":      {oem: "Nokia", model: "Unknown"},
+	}}
+}
+
+func TestGroupBy(t *testing.T) {
+	cells := groupCatalog()
+	size, err := FieldMetric("size")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byOEMAndYear := GroupBy(cells, ByPair(ByOEM, ByYear), Mean(size))
+	wantMeans := Result[map[Pair[string, uint]]float64]{
+		Value: map[Pair[string, uint]]float64{
+			{"Google", 2018}:  5.5,
+			{"Google", 2019}:  6,
+			{"Samsung", 2019}: 6.1,
+		},
+		Included: 4,
+		Excluded: 2,
+	}
+	if !reflect.DeepEqual(byOEMAndYear, wantMeans) {
+		t.Errorf("GroupBy(ByPair(ByOEM, ByYear), Mean(size)) = %+v; want %+v", byOEMAndYear, wantMeans)
+	}
+
+	byDecade := GroupBy(cells, ByDecade, Count())
+	wantCounts := Result[map[uint]int]{Value: map[uint]int{2000: 1, 2010: 4}, Included: 5, Excluded: 1}
+	if !reflect.DeepEqual(byDecade, wantCounts) {
+		t.Errorf("GroupBy(ByDecade, Count()) = %+v; want %+v", byDecade, wantCounts)
+	}
+
+	aggregates := []struct {
+		name string
+		agg  Aggregate[float64]
+		want map[string]float64
+	}{
+		{"Sum", Sum(weight), map[string]float64{"Google": 503, "Nokia": 133}},
+		{"Mean", Mean(weight), map[string]float64{"Google": 503.0 / 3, "Nokia": 133}},
+		{"Median", Median(weight), map[string]float64{"Google": 162, "Nokia": 133}},
+		{"Min", Min(weight), map[string]float64{"Google": 148, "Nokia": 133}},
+		{"Max", Max(weight), map[string]float64{"Google": 193, "Nokia": 133}},
+	}
+	for _, a := range aggregates {
+		if got := GroupBy(cells, ByOEM, a.agg).Value; !reflect.DeepEqual(got, a.want) {
+			t.Errorf("GroupBy(ByOEM, %s(weight)) = %v; want %v", a.name, got, a.want)
+		}
+	}
+
+	heaviest := GroupBy(cells, ByOEM, ArgMax(weight)).Value
+	if len(heaviest) != 2 || heaviest["Google"].model != "Pixel 4 XL" || heaviest["Nokia"].model != "3310" {
+		t.Errorf("GroupBy(ByOEM, ArgMax(weight)) = %v; want Pixel 4 XL and 3310", heaviest)
+	}
+}
+
+func TestFieldKey(t *testing.T) {
+	key, err := FieldKey("oem", "year")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := GroupBy(groupCatalog(), key, Count()).Value
+
+	keys := make([]FieldValues, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	SortFieldValues(keys)
+
+	var got [][]string
+	for _, k := range keys {
+		got = append(got, k.Values())
+	}
+	want := [][]string{{"Google", "2018"}, {"Google", "2019"}, {"Nokia", "2000"}, {"Samsung", "2019"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldKey(oem, year) groups = %v; want %v", got, want)
+	}
+
+	for _, names := range [][]string{nil, {"oem", "colour"}} {
+		if _, err := FieldKey(names...); err == nil {
+			t.Errorf("FieldKey(%q) succeeded; want an error", names)
+		}
+	}
+	for _, name := range []string{"colour", "oem"} {
+		if _, err := FieldMetric(name); err == nil {
+			t.Errorf("FieldMetric(%q) succeeded; want an error", name)
+		}
+	}
+}
+
+func TestSortFieldValues(t *testing.T) {
+	keys := []FieldValues{"10", "9", "LG", "Apple", "2.5"}
+	SortFieldValues(keys)
+	want := []FieldValues{"2.5", "9", "10", "Apple", "LG"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("SortFieldValues() = %q; want %q", keys, want)
+	}
+}
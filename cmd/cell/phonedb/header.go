@@ -0,0 +1,93 @@
+package phonedb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Names of the cells.csv columns the loader reads.
+const (
+	ColumnOEM               = "oem"
+	ColumnModel             = "model"
+	ColumnLaunchAnnounced   = "launch_announced"
+	ColumnLaunchStatus      = "launch_status"
+	ColumnBodyDimensions    = "body_dimensions"
+	ColumnBodyWeight        = "body_weight"
+	ColumnBodySim           = "body_sim"
+	ColumnDisplayType       = "display_type"
+	ColumnDisplaySize       = "display_size"
+	ColumnDisplayResolution = "display_resolution"
+	ColumnFeaturesSensors   = "features_sensors"
+	ColumnPlatformOS        = "platform_os"
+)
+
+// RequiredColumns lists the columns every cells.csv header must contain,
+// in the order they appear in the reference dataset.
+var RequiredColumns = []string{
+	ColumnOEM,
+	ColumnModel,
+	ColumnLaunchAnnounced,
+	ColumnLaunchStatus,
+	ColumnBodyDimensions,
+	ColumnBodyWeight,
+	ColumnBodySim,
+	ColumnDisplayType,
+	ColumnDisplaySize,
+	ColumnDisplayResolution,
+	ColumnFeaturesSensors,
+	ColumnPlatformOS,
+}
+
+// ErrMissingColumns is returned, wrapped, when a header lacks one or more of
+// the RequiredColumns.
+var ErrMissingColumns = errors.New("missing required columns")
+
+// Header maps column names to their position in a cells.csv record.
+type Header struct {
+	index map[string]int
+}
+
+// NewHeader builds a Header from the names in the first line of a cells.csv
+// file. Names are matched case-insensitively and surrounding whitespace is
+// ignored. Unknown columns are tolerated, but a missing required column or a
+// column named twice is an error.
+func NewHeader(names []string) (*Header, error) {
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		// strip a UTF-8 byte order mark left at the start of the file
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("duplicate column %q in header", name)
+		}
+		index[name] = i
+	}
+
+	var missing []string
+	for _, name := range RequiredColumns {
+		if _, ok := index[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingColumns, strings.Join(missing, ", "))
+	}
+
+	return &Header{index: index}, nil
+}
+
+// Value returns the field of line in the named column. It returns an empty
+// string if the header has no such column or the line is too short.
+func (h *Header) Value(line []string, name string) string {
+	i, ok := h.index[name]
+	if !ok || i >= len(line) {
+		return ""
+	}
+	return line[i]
+}
@@ -0,0 +1,99 @@
+package phonedb
+
+import (
+	"math"
+)
+
+// Bin is a range of values of a histogram and the number of phones whose
+// value falls in it. A value equal to High belongs to the next bin.
+type Bin struct {
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+	Count int     `json:"count"`
+}
+
+// Histogram counts the phones whose value of metric falls in each of at
+// most bins bins of the same width, from the bin holding the smallest value
+// to the bin holding the largest. The width is a round number such as 0.5,
+// 25 or 50, so the bins start at multiples of it. Phones for which metric
+// is unknown are excluded.
+func (c *Catalog) Histogram(metric Metric, bins int) Result[[]Bin] {
+	cells, excluded := c.partition(metric.known)
+	result := Result[[]Bin]{Value: []Bin{}, Included: len(cells), Excluded: excluded}
+	if len(cells) == 0 || bins < 1 {
+		return result
+	}
+
+	values := make([]float64, len(cells))
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for i, cell := range cells {
+		values[i] = metric.value(cell)
+		lowest = math.Min(lowest, values[i])
+		highest = math.Max(highest, values[i])
+	}
+
+	width := binWidth(lowest, highest, bins)
+	first := width.index(lowest)
+	result.Value = make([]Bin, width.index(highest)-first+1)
+	for i := range result.Value {
+		result.Value[i] = Bin{Low: width.times(first + i), High: width.times(first + i + 1)}
+	}
+	for _, v := range values {
+		result.Value[width.index(v)-first].Count++
+	}
+	return result
+}
+
+// roundWidth is a round bin width, step times ten to the power exp.
+type roundWidth struct {
+	step float64
+	exp  int
+}
+
+// size returns the width.
+func (w roundWidth) size() float64 {
+	return w.times(1)
+}
+
+// times returns n times the width. Dividing by a power of ten rather than
+// multiplying by its inverse keeps the result as close to the decimal as
+// possible, so that 3 times 0.1 is 0.3 rather than 0.30000000000000004.
+func (w roundWidth) times(n int) float64 {
+	if w.exp < 0 {
+		return float64(n) * w.step / math.Pow10(-w.exp)
+	}
+	return float64(n) * w.step * math.Pow10(w.exp)
+}
+
+// index returns the number of widths from 0 to the start of the bin
+// holding v, comparing v with the bin edges themselves so that 0.3 falls in
+// the bin from 0.3 to 0.4 even though 0.3 / 0.1 is 2.9999999999999996.
+func (w roundWidth) index(v float64) int {
+	i := int(math.Floor(v / w.size()))
+	switch {
+	case w.times(i+1) <= v:
+		i++
+	case w.times(i) > v:
+		i--
+	}
+	return i
+}
+
+// binWidth returns the smallest round width, 1, 2, 2.5 or 5 times a power of
+// ten, that splits lowest to highest into at most bins bins starting at
+// multiples of the width.
+func binWidth(lowest, highest float64, bins int) roundWidth {
+	if highest <= lowest {
+		return roundWidth{step: 1}
+	}
+	exp := int(math.Floor(math.Log10((highest - lowest) / float64(bins))))
+	for {
+		for _, step := range []float64{1, 2, 2.5, 5} {
+			width := roundWidth{step, exp}
+			if width.index(highest)-width.index(lowest)+1 <= bins {
+				return width
+			}
+		}
+		exp++
+	}
+}
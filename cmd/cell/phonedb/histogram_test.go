@@ -0,0 +1,40 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	sizes := func(values ...float64) *Catalog {
+		cells := &Catalog{cells: map[string]*Cell{"Unknown-Size": {}}}
+		for i, v := range values {
+			cells.cells[string(rune('a'+i))] = &Cell{displaySize: Some(v)}
+		}
+		return cells
+	}
+	size, _ := FieldMetric("display_size")
+
+	tests := []struct {
+		name  string
+		cells *Catalog
+		bins  int
+		want  []Bin
+	}{
+		{"no values", sizes(), 10, []Bin{}},
+		{"one value", sizes(6.1), 10, []Bin{{6, 7, 1}}},
+		// 0.3 / 0.1 is 2.9999999999999996, but 0.3 still starts a bin
+		{"decimal edges", sizes(0.1, 0.3, 0.55), 5, []Bin{{0.1, 0.2, 1}, {0.2, 0.3, 0}, {0.3, 0.4, 1}, {0.4, 0.5, 0}, {0.5, 0.6, 1}}},
+		{"round width", sizes(41, 99, 100, 730), 20, []Bin{
+			{0, 50, 1}, {50, 100, 1}, {100, 150, 1}, {150, 200, 0}, {200, 250, 0}, {250, 300, 0}, {300, 350, 0},
+			{350, 400, 0}, {400, 450, 0}, {450, 500, 0}, {500, 550, 0}, {550, 600, 0}, {600, 650, 0}, {650, 700, 0}, {700, 750, 1},
+		}},
+	}
+	for _, tt := range tests {
+		got := tt.cells.Histogram(size, tt.bins)
+		want := Result[[]Bin]{Value: tt.want, Included: tt.cells.Len() - 1, Excluded: 1}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: cells.Histogram(size, %d) = %+v; want %+v", tt.name, tt.bins, got, want)
+		}
+	}
+}
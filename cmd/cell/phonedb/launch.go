@@ -0,0 +1,294 @@
+package phonedb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Precision is how much of a Date is known.
+type Precision int
+
+const (
+	// PrecisionUnknown means no date is known.
+	PrecisionUnknown Precision = iota
+	// PrecisionYear means only the year is known.
+	PrecisionYear
+	// PrecisionQuarter means the year and quarter are known.
+	PrecisionQuarter
+	// PrecisionMonth means the year and month are known.
+	PrecisionMonth
+	// PrecisionDay means the full date is known.
+	PrecisionDay
+)
+
+// String implements the Stringer interface for Precision.
+func (p Precision) String() string {
+	switch p {
+	case PrecisionYear:
+		return "year"
+	case PrecisionQuarter:
+		return "quarter"
+	case PrecisionMonth:
+		return "month"
+	case PrecisionDay:
+		return "day"
+	default:
+		return "unknown"
+	}
+}
+
+// Date is a calendar date known to a given Precision. Fields finer than the
+// precision are zero, except Quarter which is filled in whenever the month
+// is known.
+type Date struct {
+	Year      uint
+	Quarter   int
+	Month     time.Month
+	Day       int
+	Precision Precision
+}
+
+// Known reports whether at least the year of the date is known.
+func (d Date) Known() bool {
+	return d.Precision != PrecisionUnknown
+}
+
+// year returns the year of the date, which is unknown if the date is.
+func (d Date) year() Optional[uint] {
+	if !d.Known() {
+		return Optional[uint]{}
+	}
+	return Some(d.Year)
+}
+
+// String implements the Stringer interface for Date, using the same layout
+// as the dataset, such as "2019, October 28" or "2005, Q1".
+func (d Date) String() string {
+	switch d.Precision {
+	case PrecisionYear:
+		return fmt.Sprintf("%d", d.Year)
+	case PrecisionQuarter:
+		return fmt.Sprintf("%d, Q%d", d.Year, d.Quarter)
+	case PrecisionMonth:
+		return fmt.Sprintf("%d, %s", d.Year, d.Month)
+	case PrecisionDay:
+		return fmt.Sprintf("%d, %s %d", d.Year, d.Month, d.Day)
+	default:
+		return "-"
+	}
+}
+
+// Status is the availability of a phone.
+type Status int
+
+const (
+	// StatusUnknown means the launch status could not be determined.
+	StatusUnknown Status = iota
+	// StatusAvailable means the phone is on sale.
+	StatusAvailable
+	// StatusDiscontinued means the phone is no longer made.
+	StatusDiscontinued
+	// StatusCancelled means the phone was never released.
+	StatusCancelled
+	// StatusComingSoon means the phone has not been released yet.
+	StatusComingSoon
+)
+
+// String implements the Stringer interface for Status.
+func (s Status) String() string {
+	switch s {
+	case StatusAvailable:
+		return "Available"
+	case StatusDiscontinued:
+		return "Discontinued"
+	case StatusCancelled:
+		return "Cancelled"
+	case StatusComingSoon:
+		return "Coming soon"
+	default:
+		return "Unknown"
+	}
+}
+
+// LaunchInfo describes when a phone was announced and released, and whether
+// it is still available.
+type LaunchInfo struct {
+	Announced Date
+	Released  Date
+	Status    Status
+}
+
+// String implements the Stringer interface for LaunchInfo, describing the
+// status and release date like the launch_status column does.
+func (l LaunchInfo) String() string {
+	if !l.Released.Known() {
+		return l.Status.String()
+	}
+	if l.Status == StatusComingSoon {
+		return fmt.Sprintf("%s. Exp. release %s", l.Status, l.Released)
+	}
+	return fmt.Sprintf("%s. Released %s", l.Status, l.Released)
+}
+
+// dateRe matches a 4-digit year optionally followed by a quarter, or by a
+// month name or abbreviation and a day, as in "2019, October 28" or "2005,Q1".
+var dateRe = regexp.MustCompile(`\b(\d{4})\b(?:\s*,\s*(?:Q([1-4])|([A-Za-z]{3,})\.?(?:\s+(\d{1,2})\b)?))?`)
+
+// ParseDate extracts the first date in a string, with as much precision as
+// it provides. If no 4-digit year is found it returns nil.
+func ParseDate(dateStr string) *Date {
+	match := dateRe.FindStringSubmatch(dateStr)
+	if match == nil {
+		return nil
+	}
+
+	year, err := strconv.Atoi(match[1])
+	if err != nil {
+		return nil
+	}
+	date := Date{Year: uint(year), Precision: PrecisionYear}
+
+	if match[2] != "" {
+		date.Quarter, _ = strconv.Atoi(match[2])
+		date.Precision = PrecisionQuarter
+		return &date
+	}
+
+	month, ok := parseMonth(match[3])
+	if !ok {
+		return &date
+	}
+	date.Month = month
+	date.Quarter = int(month-1)/3 + 1
+	date.Precision = PrecisionMonth
+
+	if day, err := strconv.Atoi(match[4]); err == nil && day >= 1 && day <= 31 {
+		date.Day = day
+		date.Precision = PrecisionDay
+	}
+
+	return &date
+}
+
+// parseMonth converts an English month name or its three letter
+// abbreviation to a time.Month.
+func parseMonth(name string) (time.Month, bool) {
+	if len(name) < 3 {
+		return 0, false
+	}
+	prefix := strings.ToLower(name[:3])
+	for m := time.January; m <= time.December; m++ {
+		full := strings.ToLower(m.String())
+		if full[:3] == prefix && strings.HasPrefix(full, strings.ToLower(name)) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// ParseStatus determines the Status from the start of a launch_status string.
+func ParseStatus(statusStr string) Status {
+	s := strings.ToLower(strings.TrimSpace(statusStr))
+	switch {
+	case strings.HasPrefix(s, "available"):
+		return StatusAvailable
+	case strings.HasPrefix(s, "discontinued"):
+		return StatusDiscontinued
+	case strings.HasPrefix(s, "cancelled"):
+		return StatusCancelled
+	case strings.HasPrefix(s, "coming soon"):
+		return StatusComingSoon
+	default:
+		return StatusUnknown
+	}
+}
+
+// releaseRe matches the start of a release date, either an actual or an
+// expected one.
+var releaseRe = regexp.MustCompile(`(?i)(?:released|exp\. release)\s*`)
+
+// ParseLaunch builds a LaunchInfo from the launch_announced and launch_status
+// columns. The release date may appear in either column, as in
+// "2010, January. Released 2010, March" or "Available. Released 2019, November".
+func ParseLaunch(announcedStr string, statusStr string) LaunchInfo {
+	var launch LaunchInfo
+
+	announcedPart, releasedPart := splitRelease(announcedStr)
+	if date := ParseDate(announcedPart); date != nil {
+		launch.Announced = *date
+	}
+	if date := ParseDate(releasedPart); date != nil {
+		launch.Released = *date
+	}
+
+	// a release date in launch_status is more up to date than one given
+	// alongside the announcement
+	_, releasedPart = splitRelease(statusStr)
+	if date := ParseDate(releasedPart); date != nil {
+		launch.Released = *date
+	}
+
+	launch.Status = ParseStatus(statusStr)
+
+	return launch
+}
+
+// splitRelease splits s at the last "Released" or "Exp. release" marker,
+// returning the text before the first marker and the text after the last.
+func splitRelease(s string) (string, string) {
+	locs := releaseRe.FindAllStringIndex(s, -1)
+	if locs == nil {
+		return s, ""
+	}
+	return s[:locs[0][0]], s[locs[len(locs)-1][1]:]
+}
+
+// daysPerMonth is the average length of a month in days.
+const daysPerMonth = 365.25 / 12
+
+// Lag is the time between a phone's announcement and its release.
+type Lag struct {
+	// length of the lag in days
+	Days float64
+	// length of the lag in months
+	Months float64
+	// precision the lag was measured at, the coarser of the two dates
+	Precision Precision
+}
+
+// Lag returns the time from announcement to release, measured at the finest
+// precision both dates share: exact days when both dates are known to the
+// day, otherwise whole months, quarters or years converted to days and
+// months. It reports false if either date is unknown.
+func (l LaunchInfo) Lag() (Lag, bool) {
+	a, r := l.Announced, l.Released
+	if !a.Known() || !r.Known() {
+		return Lag{}, false
+	}
+
+	precision := a.Precision
+	if r.Precision < precision {
+		precision = r.Precision
+	}
+
+	years := float64(r.Year) - float64(a.Year)
+	var months float64
+	switch precision {
+	case PrecisionDay:
+		announced := time.Date(int(a.Year), a.Month, a.Day, 0, 0, 0, 0, time.UTC)
+		released := time.Date(int(r.Year), r.Month, r.Day, 0, 0, 0, 0, time.UTC)
+		days := released.Sub(announced).Hours() / 24
+		return Lag{Days: days, Months: days / daysPerMonth, Precision: precision}, true
+	case PrecisionMonth:
+		months = years*12 + float64(r.Month) - float64(a.Month)
+	case PrecisionQuarter:
+		months = (years*4 + float64(r.Quarter) - float64(a.Quarter)) * 3
+	default:
+		months = years * 12
+	}
+
+	return Lag{Days: months * daysPerMonth, Months: months, Precision: precision}, true
+}
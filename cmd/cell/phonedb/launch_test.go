@@ -0,0 +1,135 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  *Date
+	}{
+		{"2019, October 28", &Date{Year: 2019, Quarter: 4, Month: time.October, Day: 28, Precision: PrecisionDay}},
+		{"2019, November", &Date{Year: 2019, Quarter: 4, Month: time.November, Precision: PrecisionMonth}},
+		{"2019, Nov", &Date{Year: 2019, Quarter: 4, Month: time.November, Precision: PrecisionMonth}},
+		{"2005, Q1", &Date{Year: 2005, Quarter: 1, Precision: PrecisionQuarter}},
+		{"2005,Q3", &Date{Year: 2005, Quarter: 3, Precision: PrecisionQuarter}},
+		{"Mid 2003", &Date{Year: 2003, Precision: PrecisionYear}},
+		{"1999", &Date{Year: 1999, Precision: PrecisionYear}},
+		{"Not announced yet", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		got := ParseDate(test.input)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseDate(%q) = %+v, want %+v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestParseLaunch(t *testing.T) {
+	tests := []struct {
+		announced string
+		status    string
+		want      LaunchInfo
+	}{
+		{
+			"2019, October 15", "Available. Released 2019, October 22",
+			LaunchInfo{
+				Announced: Date{Year: 2019, Quarter: 4, Month: time.October, Day: 15, Precision: PrecisionDay},
+				Released:  Date{Year: 2019, Quarter: 4, Month: time.October, Day: 22, Precision: PrecisionDay},
+				Status:    StatusAvailable,
+			},
+		},
+		{
+			"2010, January. Released 2010, March", "Discontinued",
+			LaunchInfo{
+				Announced: Date{Year: 2010, Quarter: 1, Month: time.January, Precision: PrecisionMonth},
+				Released:  Date{Year: 2010, Quarter: 1, Month: time.March, Precision: PrecisionMonth},
+				Status:    StatusDiscontinued,
+			},
+		},
+		{
+			"2020, Q2", "Coming soon. Exp. release 2020, Q3",
+			LaunchInfo{
+				Announced: Date{Year: 2020, Quarter: 2, Precision: PrecisionQuarter},
+				Released:  Date{Year: 2020, Quarter: 3, Precision: PrecisionQuarter},
+				Status:    StatusComingSoon,
+			},
+		},
+		{
+			"Not officially announced yet", "Cancelled",
+			LaunchInfo{Status: StatusCancelled},
+		},
+	}
+
+	for _, test := range tests {
+		got := ParseLaunch(test.announced, test.status)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseLaunch(%q, %q) = %+v, want %+v", test.announced, test.status, got, test.want)
+		}
+	}
+}
+
+func TestLaunchInfoString(t *testing.T) {
+	launch := LaunchInfo{
+		Released: Date{Year: 2019, Quarter: 4, Month: time.November, Precision: PrecisionMonth},
+		Status:   StatusAvailable,
+	}
+	if got, want := launch.String(), "Available. Released 2019, November"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestLaunchInfoLag(t *testing.T) {
+	tests := []struct {
+		name   string
+		launch LaunchInfo
+		want   Lag
+		ok     bool
+	}{
+		{
+			"days",
+			LaunchInfo{
+				Announced: Date{Year: 2019, Quarter: 4, Month: time.October, Day: 15, Precision: PrecisionDay},
+				Released:  Date{Year: 2019, Quarter: 4, Month: time.October, Day: 22, Precision: PrecisionDay},
+			},
+			Lag{Days: 7, Months: 7 / daysPerMonth, Precision: PrecisionDay}, true,
+		},
+		{
+			"month and day",
+			LaunchInfo{
+				Announced: Date{Year: 2019, Quarter: 4, Month: time.November, Precision: PrecisionMonth},
+				Released:  Date{Year: 2020, Quarter: 1, Month: time.January, Day: 10, Precision: PrecisionDay},
+			},
+			Lag{Days: 2 * daysPerMonth, Months: 2, Precision: PrecisionMonth}, true,
+		},
+		{
+			"quarter",
+			LaunchInfo{
+				Announced: Date{Year: 2005, Quarter: 1, Precision: PrecisionQuarter},
+				Released:  Date{Year: 2005, Quarter: 2, Month: time.May, Precision: PrecisionMonth},
+			},
+			Lag{Days: 3 * daysPerMonth, Months: 3, Precision: PrecisionQuarter}, true,
+		},
+		{
+			"unknown release",
+			LaunchInfo{Announced: Date{Year: 2005, Precision: PrecisionYear}},
+			Lag{}, false,
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := test.launch.Lag()
+
+		if ok != test.ok || !almostEqual(got.Days, test.want.Days, 1e-9) ||
+			!almostEqual(got.Months, test.want.Months, 1e-9) || got.Precision != test.want.Precision {
+			t.Errorf("%s: Lag() = %+v, %v, want %+v, %v", test.name, got, ok, test.want, test.ok)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package phonedb
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrorMode selects how a Loader reacts to a malformed row.
+type ErrorMode int
+
+const (
+	// SkipAndReport records malformed rows in the LoadReport and keeps reading.
+	SkipAndReport ErrorMode = iota
+	// FailFast stops at the first malformed row and returns it as the error.
+	FailFast
+)
+
+// ErrMissingKey is returned, wrapped in a RowError, for rows missing both
+// the OEM and the model, which together identify a phone in the Catalog.
+var ErrMissingKey = errors.New("row has neither oem nor model")
+
+// RowError describes a single cells.csv row that could not be loaded.
+type RowError struct {
+	// line number of the row in the file, counting the header as line 1
+	Line int
+	// fields of the row, if the csv reader was able to split it
+	Record []string
+	// reason the row was rejected
+	Err error
+}
+
+// Error implements the error interface for RowError.
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the reason the row was rejected.
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// LoadReport summarizes a call to Load.
+type LoadReport struct {
+	// number of data rows read, not counting the header
+	Rows int
+	// number of phones added to the catalog, which is the number of phones
+	// it holds
+	Loaded int
+	// number of rows that replaced, or were merged into, the phone loaded
+	// from an earlier row with the same OEM and model
+	Replaced int
+	// rows that were skipped, in file order
+	Errors []*RowError
+	// rows that were loaded after placeholders were cleared or shifted
+	// values were moved back into their columns, in file order
+	Repaired []*RowRepair
+	// rows that were held back because their values are in the wrong
+	// columns, in file order; their errors wrap ErrShiftedRow
+	Quarantined []*RowError
+	// rows whose OEM and model were already loaded, in file order
+	Duplicates []*DuplicateRow
+}
+
+// DuplicateRow describes a cells.csv row whose OEM and model collided with
+// an earlier row.
+type DuplicateRow struct {
+	// line number of the row in the file, counting the header as line 1
+	Line int
+	// line number of the row it collided with
+	PreviousLine int
+	*Collision
+}
+
+// String implements the Stringer interface for DuplicateRow.
+func (d *DuplicateRow) String() string {
+	same := "differs from"
+	if d.Identical {
+		same = "repeats"
+	}
+	return fmt.Sprintf("line %d: %s %s line %d, resolved by %s", d.Line, d.Key, same, d.PreviousLine, d.Policy)
+}
+
+// RowRepair describes the changes made to a cells.csv row so it could be
+// loaded.
+type RowRepair struct {
+	// line number of the row in the file, counting the header as line 1
+	Line int
+	// columns whose placeholder value was cleared
+	Placeholders []string
+	// number of columns the values were moved to the right, negative if
+	// they were moved left
+	Shift int
+}
+
+// String implements the Stringer interface for RowRepair.
+func (r *RowRepair) String() string {
+	var changes []string
+	if len(r.Placeholders) > 0 {
+		changes = append(changes, "cleared placeholders in "+strings.Join(r.Placeholders, ", "))
+	}
+	switch {
+	case r.Shift > 0:
+		changes = append(changes, fmt.Sprintf("moved values %d columns right", r.Shift))
+	case r.Shift < 0:
+		changes = append(changes, fmt.Sprintf("moved values %d columns left", -r.Shift))
+	}
+	return fmt.Sprintf("line %d: %s", r.Line, strings.Join(changes, "; "))
+}
+
+// Loader reads cells.csv data into a Catalog. The zero value skips malformed
+// rows and reports them.
+type Loader struct {
+	// what to do with malformed rows
+	Mode ErrorMode
+	// what to do with rows whose OEM and model were already loaded
+	Duplicates DuplicatePolicy
+}
+
+// Load reads phones from r using a zero Loader, which skips malformed rows
+// and lists them in the returned LoadReport.
+func Load(r io.Reader) (*Catalog, *LoadReport, error) {
+	return Loader{}.Load(r)
+}
+
+// Load reads phones from r, which must contain cells.csv formatted data
+// including its header line. Errors reading the header or the underlying
+// reader are always returned. Malformed rows are handled according to
+// l.Mode; in FailFast mode the returned error is a *RowError and the
+// catalog is nil. Every row is checked with Header.CheckRecord: repaired
+// rows are loaded and listed in the report, and rows that cannot be
+// repaired are quarantined in the report in either mode. Rows repeating the
+// OEM and model of an earlier row are resolved according to l.Duplicates and
+// listed in the report.
+func (l Loader) Load(r io.Reader) (*Catalog, *LoadReport, error) {
+	catalog := NewCatalog()
+	report := &LoadReport{}
+	// line each catalog key was last stored from, to report collisions
+	lines := make(map[string]int)
+
+	// create a csv reader from r. Rows may have more or fewer fields than
+	// the header, as when a stray comma shifts the values of a row; those
+	// are left to CheckRecord to realign
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	// read the first line of cells.csv and map each column name to its position
+	names, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, report, errors.New("read header: input is empty")
+		}
+		return nil, report, fmt.Errorf("read header: %w", err)
+	}
+	header, err := NewHeader(names)
+	if err != nil {
+		return nil, report, err
+	}
+
+	// for loop continuing until the end of the input
+	for {
+		// reads the next line from the csv, if there is an error in this
+		// operation err will not be nil, stores a record of the fields in line
+		line, err := reader.Read()
+		// reaching the end of the input is the only way out of a successful load
+		if err == io.EOF {
+			break
+		}
+
+		var rowErr *RowError
+		var parseErr *csv.ParseError
+		var lineNum int
+		var check RowCheck
+		switch {
+		case errors.As(err, &parseErr):
+			// a malformed row, the reader can carry on with the next one
+			report.Rows++
+			rowErr = &RowError{Line: parseErr.StartLine, Record: line, Err: parseErr.Err}
+		case err != nil:
+			// anything else is a problem with the underlying reader
+			return nil, report, err
+		default:
+			report.Rows++
+			lineNum, _ = reader.FieldPos(0)
+			// clear placeholders and undo column shifts
+			check, rowErr = header.checkRow(line, lineNum)
+			if header.Value(line, ColumnOEM) == "" && header.Value(line, ColumnModel) == "" {
+				rowErr = &RowError{Line: lineNum, Record: line, Err: ErrMissingKey}
+			}
+		}
+
+		if rowErr != nil {
+			report.Errors = append(report.Errors, rowErr)
+			if l.Mode == FailFast {
+				return nil, report, rowErr
+			}
+			continue
+		}
+
+		// hold back rows that cannot be realigned
+		if check.Err != nil {
+			report.Quarantined = append(report.Quarantined, &RowError{Line: lineNum, Record: line, Err: check.Err})
+			continue
+		}
+		if check.Repaired() {
+			report.Repaired = append(report.Repaired, &RowRepair{Line: lineNum, Placeholders: check.Placeholders, Shift: check.Shift})
+		}
+
+		// insert the parsed cell into the catalog, noting any earlier row
+		// with the same oem and model
+		cell := header.ParseRecord(check.Record)
+		cell.line = lineNum
+		key := Key(cell.oem, cell.model)
+		collision := catalog.Insert(cell, l.Duplicates)
+		if collision != nil {
+			report.Duplicates = append(report.Duplicates, &DuplicateRow{Line: lineNum, PreviousLine: lines[key], Collision: collision})
+			if collision.StoredAs == "" {
+				continue
+			}
+			if collision.StoredAs == key {
+				// the row took the place of the phone stored from the
+				// earlier row, so no phone was added
+				lines[key] = lineNum
+				report.Replaced++
+				continue
+			}
+			key = collision.StoredAs
+		}
+		lines[key] = lineNum
+		report.Loaded++
+	}
+
+	return catalog, report, nil
+}
+
+// checkRow runs a row read from line lineNum through CheckRecord. Only a
+// row whose values were shifted may have more or fewer fields than the
+// header; for any other such row it also returns a RowError wrapping
+// csv.ErrFieldCount.
+func (h *Header) checkRow(line []string, lineNum int) (RowCheck, *RowError) {
+	check := h.CheckRecord(line)
+	if len(line) != h.width() && check.Err == nil && check.Shift == 0 {
+		return check, &RowError{Line: lineNum, Record: line, Err: csv.ErrFieldCount}
+	}
+	return check, nil
+}
+
+// ParseRecord creates a new Cell from a single cells.csv record, looking up
+// each column by name and running it through its parser. Columns that fail to
+// parse are stored as unknown values.
+func (h *Header) ParseRecord(line []string) *Cell {
+	// Parse the announcement, release and status from the launch columns of the line.
+	// Any date that fails to parse is left unknown
+	launch := ParseLaunch(h.Value(line, ColumnLaunchAnnounced), h.Value(line, ColumnLaunchStatus))
+
+	// Parse every other column of the line. A parser returns nil when it
+	// finds nothing, which FromPtr turns into an unknown value
+	dimensions := FromPtr(ParseDimensions(h.Value(line, ColumnBodyDimensions)))
+	weight := FromPtr(ParseWeight(h.Value(line, ColumnBodyWeight)))
+	sim := FromPtr(ParseSim(h.Value(line, ColumnBodySim)))
+	displayType := FromPtr(ParseDisplayType(h.Value(line, ColumnDisplayType)))
+	size := FromPtr(ParseSize(h.Value(line, ColumnDisplaySize)))
+	resolution := FromPtr(ParseResolution(h.Value(line, ColumnDisplayResolution)))
+	sensors := FromPtr(ParseSensors(h.Value(line, ColumnFeaturesSensors)))
+	osPlat := FromPtr(ParsePlatformOS(h.Value(line, ColumnPlatformOS)))
+
+	// create a new Cell using the NewCell func pulling data from
+	// the line record containing all cell fields
+	return NewCell(h.Value(line, ColumnOEM), h.Value(line, ColumnModel),
+		launch, dimensions, weight, sim,
+		displayType, size,
+		resolution, sensors, osPlat)
+}
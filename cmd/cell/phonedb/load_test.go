@@ -0,0 +1,184 @@
+package phonedb
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadReorderedAndExtraColumns(t *testing.T) {
+	data := "platform_os,extra,model,oem,launch_announced,launch_status,body_dimensions,body_weight,body_sim,display_type,display_size,display_resolution,features_sensors\n" +
+		"Android 10,ignored,Pixel 4 XL,Google,2019,Available,-,193 g (6.81 oz),Nano-SIM,OLED,6.3 inches,-,Accelerometer\n"
+
+	catalog, _, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	cell := catalog.Get("Google", "Pixel 4 XL")
+	if cell == nil {
+		t.Fatalf("Load() did not load Google Pixel 4 XL, got %v", catalog.Cells())
+	}
+	if cell.LaunchAnnounced() != Some[uint](2019) {
+		t.Errorf("LaunchAnnounced() = %v, want 2019", cell.LaunchAnnounced())
+	}
+	if cell.BodyWeight() != Some[float64](193) {
+		t.Errorf("BodyWeight() = %v, want 193", cell.BodyWeight())
+	}
+	if cell.PlatformOS().Value().Name() != "Android 10.0" {
+		t.Errorf("PlatformOS().Name() = %q, want %q", cell.PlatformOS().Value().Name(), "Android 10.0")
+	}
+	// body_dimensions and display_resolution are "-"
+	if cell.BodyDimensions().Known() || cell.DisplayResolution().Known() {
+		t.Errorf("BodyDimensions() = %v, DisplayResolution() = %v; want both unknown", cell.BodyDimensions(), cell.DisplayResolution())
+	}
+}
+
+func TestLoadMissingColumns(t *testing.T) {
+	data := "oem,model,launch_announced\nGoogle,Pixel 4 XL,2019\n"
+
+	_, _, err := Load(strings.NewReader(data))
+	if !errors.Is(err, ErrMissingColumns) {
+		t.Fatalf("Load() error = %v, want %v", err, ErrMissingColumns)
+	}
+	if !strings.Contains(err.Error(), ColumnPlatformOS) {
+		t.Errorf("Load() error = %q, want it to name %q", err, ColumnPlatformOS)
+	}
+}
+
+func TestNewHeader(t *testing.T) {
+	names := append([]string{"\ufeff OEM "}, RequiredColumns[1:]...)
+	header, err := NewHeader(names)
+	if err != nil {
+		t.Fatalf("NewHeader() error = %v", err)
+	}
+	if got := header.Value([]string{"Apple"}, ColumnOEM); got != "Apple" {
+		t.Errorf("Value(oem) = %q, want %q", got, "Apple")
+	}
+	if got := header.Value([]string{"Apple"}, ColumnModel); got != "" {
+		t.Errorf("Value(model) on short line = %q, want empty", got)
+	}
+
+	duplicate := append(append([]string{}, RequiredColumns...), ColumnOEM)
+	if _, err := NewHeader(duplicate); err == nil {
+		t.Errorf("NewHeader() with duplicate column returned nil error")
+	}
+}
+
+func TestLoadSkipAndReport(t *testing.T) {
+	data := strings.Join(RequiredColumns, ",") + "\n" +
+		"Google,Pixel 4 XL,2019,Available,-,193 g,Nano-SIM,OLED,6.3 inches,-,Accelerometer,Android 10\n" +
+		"Google,Pixel \"3,2018,Available,-,148 g,Nano-SIM,OLED,5.5 inches,-,Accelerometer,Android 9\n" +
+		",,2018,Available,-,148 g,Nano-SIM,OLED,5.5 inches,-,Accelerometer,Android 9\n" +
+		"Google,Pixel 2,2017\n" +
+		"Samsung,Galaxy S10,2019,Available,-,157 g,Nano-SIM,AMOLED,6.1 inches,-,Accelerometer,Android 9\n"
+
+	catalog, report, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if catalog.Len() != 2 {
+		t.Errorf("catalog.Len() = %d, want 2", catalog.Len())
+	}
+	if report.Rows != 5 || report.Loaded != 2 {
+		t.Errorf("report rows/loaded = %d/%d, want 5/2", report.Rows, report.Loaded)
+	}
+
+	wantLines := []int{3, 4, 5}
+	if len(report.Errors) != len(wantLines) {
+		t.Fatalf("len(report.Errors) = %d, want %d: %v", len(report.Errors), len(wantLines), report.Errors)
+	}
+	for i, line := range wantLines {
+		if report.Errors[i].Line != line {
+			t.Errorf("report.Errors[%d].Line = %d, want %d", i, report.Errors[i].Line, line)
+		}
+	}
+	if !errors.Is(report.Errors[1], ErrMissingKey) {
+		t.Errorf("report.Errors[1] = %v, want %v", report.Errors[1], ErrMissingKey)
+	}
+}
+
+func TestLoadFailFast(t *testing.T) {
+	data := strings.Join(RequiredColumns, ",") + "\n" +
+		"Google,Pixel 2,2017\n" +
+		"Samsung,Galaxy S10,2019,Available,-,157 g,Nano-SIM,AMOLED,6.1 inches,-,Accelerometer,Android 9\n"
+
+	catalog, report, err := Loader{Mode: FailFast}.Load(strings.NewReader(data))
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 2 {
+		t.Fatalf("Load() error = %v, want a RowError on line 2", err)
+	}
+	if catalog != nil {
+		t.Errorf("Load() catalog = %v, want nil", catalog)
+	}
+	if report.Rows != 1 {
+		t.Errorf("report.Rows = %d, want 1", report.Rows)
+	}
+}
+
+func TestLoadEmpty(t *testing.T) {
+	if _, _, err := Load(strings.NewReader("")); err == nil {
+		t.Errorf("Load() of empty input returned nil error")
+	}
+}
+
+func TestLoadReportsDuplicates(t *testing.T) {
+	data := strings.Join(RequiredColumns, ",") + "\n" +
+		"Google,Pixel 4,2019,Available,-,162 g,Nano-SIM,OLED,5.7 inches,-,Accelerometer,Android 10\n" +
+		"Google,Pixel 4,2019,Available,-,162 g,Nano-SIM,OLED,5.7 inches,-,Accelerometer,Android 10\n" +
+		"Google,Pixel 4,2019,Available,-,165 g,Nano-SIM,OLED,5.7 inches,-,Accelerometer,Android 10\n"
+
+	catalog, report, err := Loader{Duplicates: KeepFirst}.Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if catalog.Len() != 1 || report.Loaded != 1 {
+		t.Errorf("Load() loaded %d rows into %d phones; want 1 and 1", report.Loaded, catalog.Len())
+	}
+	if got := catalog.Get("Google", "Pixel 4").BodyWeight(); got != Some[float64](162) {
+		t.Errorf("BodyWeight() = %v; want 162 from the first row", got)
+	}
+
+	if len(report.Duplicates) != 2 {
+		t.Fatalf("LoadReport.Duplicates = %v; want 2 rows", report.Duplicates)
+	}
+	repeat, conflict := report.Duplicates[0], report.Duplicates[1]
+	if repeat.Line != 3 || repeat.PreviousLine != 2 || !repeat.Identical {
+		t.Errorf("LoadReport.Duplicates[0] = %v; want line 3 repeating line 2", repeat)
+	}
+	if conflict.Line != 4 || conflict.PreviousLine != 2 || conflict.Identical {
+		t.Errorf("LoadReport.Duplicates[1] = %v; want line 4 differing from line 2", conflict)
+	}
+}
+
+func TestLoadCountsPhonesAdded(t *testing.T) {
+	data := strings.Join(RequiredColumns, ",") + "\n" +
+		"Google,Pixel 4,2019,Available,-,162 g,Nano-SIM,OLED,5.7 inches,-,Accelerometer,Android 10\n" +
+		"Google,Pixel 4,2019,Available,-,162 g,Nano-SIM,OLED,5.7 inches,-,Accelerometer,Android 10\n" +
+		"Google,Pixel 4,2019,Available,-,165 g,Nano-SIM,OLED,5.7 inches,-,Accelerometer,Android 10\n" +
+		"Samsung,Galaxy S10,2019,Available,-,157 g,Nano-SIM,AMOLED,6.1 inches,-,Accelerometer,Android 9\n"
+
+	tests := []struct {
+		policy       DuplicatePolicy
+		wantLoaded   int
+		wantReplaced int
+	}{
+		{KeepLast, 2, 2},
+		{KeepFirst, 2, 0},
+		{KeepBoth, 4, 0},
+		{MergeFields, 2, 2},
+	}
+	for _, tt := range tests {
+		catalog, report, err := Loader{Duplicates: tt.policy}.Load(strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("Load() with %v error = %v", tt.policy, err)
+		}
+		if report.Loaded != catalog.Len() {
+			t.Errorf("Load() with %v: LoadReport.Loaded = %d; want catalog.Len() = %d", tt.policy, report.Loaded, catalog.Len())
+		}
+		if report.Loaded != tt.wantLoaded || report.Replaced != tt.wantReplaced {
+			t.Errorf("Load() with %v loaded %d and replaced %d; want %d and %d",
+				tt.policy, report.Loaded, report.Replaced, tt.wantLoaded, tt.wantReplaced)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package phonedb
+
+import "fmt"
+
+// Optional holds a value that may be missing from the dataset. The zero
+// value is an unknown value.
+type Optional[T any] struct {
+	value T
+	known bool
+}
+
+// Some returns a known Optional holding value.
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{value: value, known: true}
+}
+
+// FromPtr returns an Optional holding *ptr, or an unknown Optional if ptr is
+// nil. It turns the result of a parser into a Cell field.
+func FromPtr[T any](ptr *T) Optional[T] {
+	if ptr == nil {
+		return Optional[T]{}
+	}
+	return Some(*ptr)
+}
+
+// Known reports whether the value is present.
+func (o Optional[T]) Known() bool {
+	return o.known
+}
+
+// Value returns the value, or the zero value of T if it is unknown.
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// Get returns the value and whether it is known.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.known
+}
+
+// String implements the Stringer interface for Optional, writing "-" for an
+// unknown value.
+func (o Optional[T]) String() string {
+	if !o.known {
+		return "-"
+	}
+	return fmt.Sprint(o.value)
+}
+
+// Result is the value of an aggregate over a Catalog together with how many
+// phones it was computed from and how many were left out because a field it
+// needs is unknown.
+type Result[T any] struct {
+	Value    T   `json:"value"`
+	Included int `json:"included"`
+	Excluded int `json:"excluded"`
+}
+
+// partition returns the phones in the catalog for which known reports true,
+// in Phones order, and the number of phones for which it reports false.
+// Aggregates visit the phones in the same order every run, so sums come out
+// the same to the last bit and ties are broken the same way.
+func (c *Catalog) partition(known func(*Cell) bool) ([]*Cell, int) {
+	var included []*Cell
+	excluded := 0
+	for _, cell := range c.Phones() {
+		if known(cell) {
+			included = append(included, cell)
+		} else {
+			excluded++
+		}
+	}
+	return included, excluded
+}
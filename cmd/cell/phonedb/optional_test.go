@@ -0,0 +1,32 @@
+package phonedb
+
+import "testing"
+
+func TestOptional(t *testing.T) {
+	weight := 193.0
+	tests := []struct {
+		name      string
+		value     Optional[float64]
+		wantKnown bool
+		wantValue float64
+		wantStr   string
+	}{
+		{"Some", Some(6.3), true, 6.3, "6.3"},
+		{"FromPtr", FromPtr(&weight), true, 193, "193"},
+		{"FromPtr nil", FromPtr[float64](nil), false, 0, "-"},
+		{"zero value", Optional[float64]{}, false, 0, "-"},
+		{"known zero", Some(0.0), true, 0, "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, known := tt.value.Get()
+			if known != tt.wantKnown || value != tt.wantValue {
+				t.Errorf("Get() = %v, %v; want %v, %v", value, known, tt.wantValue, tt.wantKnown)
+			}
+			if got := tt.value.String(); got != tt.wantStr {
+				t.Errorf("String() = %q; want %q", got, tt.wantStr)
+			}
+		})
+	}
+}
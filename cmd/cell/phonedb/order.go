@@ -0,0 +1,102 @@
+package phonedb
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SortOrder selects how OEMs or phones are ordered. Every order ends with a
+// comparison of names, so the result never depends on map iteration order.
+type SortOrder int
+
+const (
+	// SortByName orders OEMs by name, and phones by OEM, then model, then
+	// the line they were loaded from, as Catalog.Phones does.
+	SortByName SortOrder = iota
+	// SortByCount orders OEMs from the most phones to the fewest, breaking
+	// ties by name. Phones have no count, so they are ordered by name.
+	SortByCount
+	// SortByLatest orders OEMs from the most recent latest model to the
+	// least recent, and phones from the most recently announced to the least
+	// recently announced. Unknown years come last and ties are broken by
+	// name.
+	SortByLatest
+)
+
+// String implements the Stringer interface for SortOrder, returning the
+// name ParseSortOrder accepts.
+func (o SortOrder) String() string {
+	switch o {
+	case SortByCount:
+		return "count"
+	case SortByLatest:
+		return "latest"
+	default:
+		return "name"
+	}
+}
+
+// ParseSortOrder returns the SortOrder named "name", "count" or "latest".
+func ParseSortOrder(s string) (SortOrder, error) {
+	for _, order := range []SortOrder{SortByName, SortByCount, SortByLatest} {
+		if order.String() == s {
+			return order, nil
+		}
+	}
+	return SortByName, fmt.Errorf("unknown sort order %q, want name, count or latest", s)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for
+// SortOrder.
+func (o SortOrder) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for
+// SortOrder, so it can be used as a flag with flag.TextVar.
+func (o *SortOrder) UnmarshalText(text []byte) error {
+	order, err := ParseSortOrder(string(text))
+	if err != nil {
+		return err
+	}
+	*o = order
+	return nil
+}
+
+// SortOEMs sorts oems in place according to order.
+func SortOEMs(oems []OEMSummary, order SortOrder) {
+	sort.Slice(oems, func(i, j int) bool {
+		a, b := oems[i], oems[j]
+		switch {
+		case order == SortByCount && a.Phones != b.Phones:
+			return a.Phones > b.Phones
+		case order == SortByLatest && a.LatestYear != b.LatestYear:
+			// unknown years are 0 and so come last
+			return a.LatestYear > b.LatestYear
+		}
+		return a.OEM < b.OEM
+	})
+}
+
+// SortPhones sorts phones in place according to order.
+func SortPhones(phones []*Cell, order SortOrder) {
+	sort.Slice(phones, func(i, j int) bool {
+		a, b := phones[i], phones[j]
+		if order == SortByLatest {
+			ya, yb := a.launch.Announced.year(), b.launch.Announced.year()
+			if ya.Known() != yb.Known() {
+				return ya.Known()
+			}
+			if ya.Value() != yb.Value() {
+				return ya.Value() > yb.Value()
+			}
+		}
+		if a.oem != b.oem {
+			return a.oem < b.oem
+		}
+		if a.model != b.model {
+			return a.model < b.model
+		}
+		return a.line < b.line
+	})
+}
@@ -0,0 +1,86 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortOEMs(t *testing.T) {
+	oems := []OEMSummary{
+		{OEM: "Sony", Phones: 2, LatestYear: 2019},
+		{OEM: "Alcatel", Phones: 3},
+		{OEM: "Nokia", Phones: 3, LatestYear: 2017},
+		{OEM: "Apple", Phones: 1, LatestYear: 2019},
+	}
+
+	tests := []struct {
+		order SortOrder
+		want  []string
+	}{
+		{SortByName, []string{"Alcatel", "Apple", "Nokia", "Sony"}},
+		{SortByCount, []string{"Alcatel", "Nokia", "Sony", "Apple"}},
+		{SortByLatest, []string{"Apple", "Sony", "Nokia", "Alcatel"}},
+	}
+	for _, tt := range tests {
+		sorted := append([]OEMSummary(nil), oems...)
+		SortOEMs(sorted, tt.order)
+		var got []string
+		for _, oem := range sorted {
+			got = append(got, oem.OEM)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SortOEMs(%v) = %q; want %q", tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestSortPhones(t *testing.T) {
+	phones := []*Cell{
+		{oem: "Nokia", model: "3310", launch: announcedIn(2000)},
+		{oem: "Google", model: "Pixel 4", launch: announcedIn(2019)},
+		{oem: "Nokia", model: "9", launch: announcedIn(0)},
+		{oem: "Apple", model: "iPhone 11", launch: announcedIn(2019)},
+		{oem: "Nokia", model: "3310", line: 3, launch: announcedIn(2017)},
+	}
+
+	tests := []struct {
+		order SortOrder
+		want  []string
+	}{
+		{SortByName, []string{"Apple iPhone 11", "Google Pixel 4", "Nokia 3310", "Nokia 3310", "Nokia 9"}},
+		{SortByLatest, []string{"Apple iPhone 11", "Google Pixel 4", "Nokia 3310", "Nokia 3310", "Nokia 9"}},
+	}
+	for _, tt := range tests {
+		sorted := append([]*Cell(nil), phones...)
+		SortPhones(sorted, tt.order)
+		var got []string
+		for _, cell := range sorted {
+			got = append(got, cell.oem+" "+cell.model)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SortPhones(%v) = %q; want %q", tt.order, got, tt.want)
+		}
+	}
+
+	// the 2017 Nokia 3310 comes before the 2000 one when sorting by latest,
+	// and after it when sorting by name, where the line decides
+	SortPhones(phones, SortByLatest)
+	if phones[2].line != 3 {
+		t.Errorf("SortPhones(latest)[2] = %v; want the 2017 Nokia 3310", phones[2])
+	}
+	SortPhones(phones, SortByName)
+	if phones[2].line != 0 {
+		t.Errorf("SortPhones(name)[2] = %v; want the 2000 Nokia 3310", phones[2])
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	for _, order := range []SortOrder{SortByName, SortByCount, SortByLatest} {
+		if got, err := ParseSortOrder(order.String()); err != nil || got != order {
+			t.Errorf("ParseSortOrder(%q) = %v, %v; want %v", order, got, err, order)
+		}
+	}
+	if _, err := ParseSortOrder("year"); err == nil {
+		t.Errorf("ParseSortOrder(%q) error = nil; want an error", "year")
+	}
+}
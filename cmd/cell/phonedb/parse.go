@@ -0,0 +1,52 @@
+package phonedb
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// ParseWeight extracts a weight in grams from a string. If no valid weight is found or if
+// an error occurs during conversion, it returns nil. Otherwise, it returns a pointer to
+// the extracted weight.
+func ParseWeight(weightStr string) *float64 {
+	// Find any number followed by " g" in the string
+	re := regexp.MustCompile("(\\d+(\\.\\d+)?)\\s* g")
+	match := re.FindStringSubmatch(weightStr)
+
+	// If no match was found, return nil
+	if len(match) == 0 {
+		return nil
+	}
+
+	// Convert the match to a float
+	weight, err := strconv.ParseFloat(match[1], 64)
+	// If an error occurred during conversion, return nil
+	if err != nil {
+		return nil
+	}
+
+	return &weight
+}
+
+// ParseSize extracts a size in inches from a string. If no valid size is found or if
+// an error occurs during conversion, it returns nil. Otherwise, it returns a pointer to
+// the extracted size.
+func ParseSize(sizeStr string) *float64 {
+	// Find any number followed by " inches" in the string
+	re := regexp.MustCompile("(\\d+(\\.\\d+)?)\\s* inches")
+	match := re.FindStringSubmatch(sizeStr)
+
+	// If no match was found, return nil
+	if len(match) == 0 {
+		return nil
+	}
+
+	// Convert the match to a float
+	size, err := strconv.ParseFloat(match[1], 64)
+	// If an error occurred during conversion, return nil
+	if err != nil {
+		return nil
+	}
+
+	return &size
+}
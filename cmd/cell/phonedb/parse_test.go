@@ -0,0 +1,55 @@
+package phonedb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseWeight(t *testing.T) {
+	tests := []struct {
+		input string
+		want  *float64
+	}{
+		{"174 g", float64Ptr(174)},
+		// decimals are kept exactly, not rounded to float32 precision
+		{"119.4 g (4.21 oz)", float64Ptr(119.4)},
+		{"Invalid weight", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		got := ParseWeight(test.input)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseWeight(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  *float64
+	}{
+		{"6.1 inches", float64Ptr(6.1)},
+		{"6.3 inches, 98.0 cm2 (~84.1% screen-to-body ratio)", float64Ptr(6.3)},
+		{"Invalid size", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		got := ParseSize(test.input)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseSize(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func almostEqual(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+// float64Ptr returns a pointer to f, as the parsers return their values
+func float64Ptr(f float64) *float64 { return &f }
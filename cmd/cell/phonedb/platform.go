@@ -0,0 +1,195 @@
+package phonedb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a dotted version number such as 4.2.2. Missing parts are zero.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion reads a version from the start of a string such as "9.0" or
+// "4.2.2". It reports false if the string does not start with a number.
+func ParseVersion(versionStr string) (Version, bool) {
+	match := versionRe.FindString(strings.TrimSpace(versionStr))
+	if match == "" {
+		return Version{}, false
+	}
+	var v Version
+	parts := strings.Split(match, ".")
+	v.Major, _ = strconv.Atoi(parts[0])
+	if len(parts) > 1 {
+		v.Minor, _ = strconv.Atoi(parts[1])
+	}
+	if len(parts) > 2 {
+		v.Patch, _ = strconv.Atoi(parts[2])
+	}
+	return v, true
+}
+
+// Less reports whether v is an earlier version than other.
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// String implements the Stringer interface for Version. The patch number is
+// only written when it is not zero.
+func (v Version) String() string {
+	if v.Patch != 0 {
+		return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// OSInfo describes the operating system a phone ships with.
+type OSInfo struct {
+	// operating system family, such as "Android" or "Windows Mobile"
+	Family string
+	// version of the operating system, if listed
+	Version    Version
+	HasVersion bool
+	// release name of the version, such as "Pie"
+	Codename string
+	// vendor skin on top of the operating system, such as "EMUI"
+	Skin string
+	// version of the vendor skin, such as "9.1"
+	SkinVersion string
+	// whether the phone ships without Google Play Services
+	NoGoogleServices bool
+}
+
+// osFamilies maps the start of a platform_os string to its family. Longer
+// prefixes are listed before the prefixes they contain.
+var osFamilies = []struct {
+	prefix string
+	family string
+}{
+	{"android", "Android"},
+	{"microsoft windows phone", "Windows Phone"},
+	{"windows phone", "Windows Phone"},
+	{"microsoft", "Windows Mobile"},
+	{"windows mobile", "Windows Mobile"},
+	{"symbian", "Symbian"},
+	{"palm", "Palm OS"},
+	{"linux", "Linux"},
+	{"kaios", "KaiOS"},
+	{"ios", "iOS"},
+	{"blackberry", "BlackBerry"},
+	{"tizen", "Tizen"},
+}
+
+var (
+	// "9.0" or "4.2.2"
+	versionRe = regexp.MustCompile(`^\d+(?:\.\d+)*`)
+	// first version number in a string, such as the "9.0" in "Android 9.0 (Pie)"
+	osVersionRe = regexp.MustCompile(`\b(\d+(?:\.\d+)*)`)
+	// Android "(Pie)", or a codename right after the version as in "9.0 Pie (Go edition)"
+	codenameRe = regexp.MustCompile(`\d\s*(?:\(([A-Z][A-Za-z ]+)\)|([A-Z][a-z]+(?: [A-Z][a-z]+)*))`)
+	// "EMUI 9.1" or "Realme UI"
+	skinRe = regexp.MustCompile(`^(.*?)\s*(\d+(?:\.\d+)*)?$`)
+)
+
+// ParsePlatformOS decomposes a platform_os string such as
+// "Android 9.0 (Pie), EMUI 9.1, no Google Play Services" into an OSInfo.
+// Upgrade notes are ignored. If the string is empty or a placeholder such as
+// "12.2", it returns nil.
+func ParsePlatformOS(osStr string) *OSInfo {
+	osStr = strings.TrimSpace(osStr)
+	if osStr == "" || isPlaceholder(osStr) {
+		return nil
+	}
+
+	segments := strings.Split(osStr, ",")
+	// variants such as "Android 9.0 (Pie) - 2GB RAM" keep the first one
+	base := strings.TrimSpace(strings.SplitN(segments[0], " - ", 2)[0])
+
+	var info OSInfo
+	info.Family = osFamily(base)
+
+	if match := osVersionRe.FindStringSubmatch(base); match != nil {
+		info.Version, info.HasVersion = ParseVersion(match[1])
+	}
+	if match := codenameRe.FindStringSubmatch(base); match != nil && info.Family == "Android" {
+		codename := match[1]
+		if codename == "" {
+			codename = match[2]
+		}
+		if codename != "Go edition" {
+			info.Codename = codename
+		}
+	}
+
+	for _, segment := range segments[1:] {
+		segment = strings.TrimSpace(segment)
+		lower := strings.ToLower(segment)
+		switch {
+		case lower == "no google play services":
+			info.NoGoogleServices = true
+		case lower == "google play services",
+			strings.Contains(lower, "upgrad"),
+			segment == "" || segment[0] >= '0' && segment[0] <= '9',
+			osFamily(segment) == info.Family && osVersionRe.MatchString(segment):
+			// upgrade notes and other versions say nothing about the skin
+		case info.Skin == "":
+			match := skinRe.FindStringSubmatch(segment)
+			info.Skin, info.SkinVersion = match[1], match[2]
+		}
+	}
+
+	return &info
+}
+
+// osFamily returns the operating system family named at the start of s. For
+// unknown families it returns s without its version number.
+func osFamily(s string) string {
+	lower := strings.ToLower(s)
+	for _, f := range osFamilies {
+		if strings.HasPrefix(lower, f.prefix) {
+			return f.family
+		}
+	}
+	if loc := osVersionRe.FindStringIndex(s); loc != nil {
+		s = s[:loc[0]]
+	}
+	return strings.TrimSpace(s)
+}
+
+// Name returns the family and version, such as "Android 9.0", which
+// identifies a distinct operating system release.
+func (o OSInfo) Name() string {
+	if !o.HasVersion {
+		return o.Family
+	}
+	return o.Family + " " + o.Version.String()
+}
+
+// String implements the Stringer interface for OSInfo, using the same layout
+// as the dataset.
+func (o OSInfo) String() string {
+	if o.Family == "" {
+		return "-"
+	}
+	s := o.Name()
+	if o.Codename != "" {
+		s += " (" + o.Codename + ")"
+	}
+	if o.Skin != "" {
+		s += ", " + strings.TrimSpace(o.Skin+" "+o.SkinVersion)
+	}
+	if o.NoGoogleServices {
+		s += ", no Google Play Services"
+	}
+	return s
+}
@@ -0,0 +1,72 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePlatformOS(t *testing.T) {
+	tests := []struct {
+		input string
+		want  *OSInfo
+	}{
+		{"10.0", nil},
+		{"", nil},
+		{"Android 10", &OSInfo{Family: "Android", Version: Version{Major: 10}, HasVersion: true}},
+		{
+			"Android 9.0 (Pie), EMUI 9.1",
+			&OSInfo{Family: "Android", Version: Version{Major: 9}, HasVersion: true, Codename: "Pie", Skin: "EMUI", SkinVersion: "9.1"},
+		},
+		{
+			"Android 10, Magic UI 3.1, no Google Play Services",
+			&OSInfo{Family: "Android", Version: Version{Major: 10}, HasVersion: true, Skin: "Magic UI", SkinVersion: "3.1", NoGoogleServices: true},
+		},
+		{
+			"Android 9.0 (Pie), upgradable to Android 10, Realme UI",
+			&OSInfo{Family: "Android", Version: Version{Major: 9}, HasVersion: true, Codename: "Pie", Skin: "Realme UI"},
+		},
+		{
+			"Android 8.1 Oreo (Go edition)",
+			&OSInfo{Family: "Android", Version: Version{Major: 8, Minor: 1}, HasVersion: true, Codename: "Oreo"},
+		},
+		{
+			"Android 4.2.2 (Jelly Bean)",
+			&OSInfo{Family: "Android", Version: Version{Major: 4, Minor: 2, Patch: 2}, HasVersion: true, Codename: "Jelly Bean"},
+		},
+		{
+			"Android 9.0 (Pie) - 2GB RAM, Android 9.0 Pie (Go edition) - 1GB RAM",
+			&OSInfo{Family: "Android", Version: Version{Major: 9}, HasVersion: true, Codename: "Pie"},
+		},
+		{
+			"Microsoft Windows Mobile 6.5.3 Professional",
+			&OSInfo{Family: "Windows Mobile", Version: Version{Major: 6, Minor: 5, Patch: 3}, HasVersion: true},
+		},
+		{"Huawei wearable platform", &OSInfo{Family: "Huawei wearable platform"}},
+	}
+
+	for _, test := range tests {
+		got := ParsePlatformOS(test.input)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParsePlatformOS(%q) = %+v, want %+v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestOSInfoString(t *testing.T) {
+	input := "Android 10, EMUI 10.1, no Google Play Services"
+	want := "Android 10.0, EMUI 10.1, no Google Play Services"
+
+	if got := ParsePlatformOS(input).String(); got != want {
+		t.Errorf("ParsePlatformOS(%q).String() = %q, want %q", input, got, want)
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	if !(Version{Major: 4, Minor: 2, Patch: 2}).Less(Version{Major: 4, Minor: 3}) {
+		t.Errorf("4.2.2 should be less than 4.3")
+	}
+	if (Version{Major: 10}).Less(Version{Major: 9}) {
+		t.Errorf("10.0 should not be less than 9.0")
+	}
+}
@@ -0,0 +1,274 @@
+package phonedb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrShiftedRow is returned, wrapped in a RowError, for rows whose values sit
+// in the wrong columns and cannot be moved back unambiguously.
+var ErrShiftedRow = errors.New("values are in the wrong columns")
+
+// placeholderRe matches filler values such as "V1" or "12.2" that stand in
+// for a missing value.
+var placeholderRe = regexp.MustCompile(`^[A-Za-z]?\d+(\.\d+)?$`)
+
+// isPlaceholder reports whether value is filler rather than data.
+func isPlaceholder(value string) bool {
+	return placeholderRe.MatchString(strings.TrimSpace(value))
+}
+
+// columnSignatures match values that clearly belong in a column. A value
+// that matches none of them is not evidence of anything.
+var columnSignatures = map[string]*regexp.Regexp{
+	ColumnLaunchAnnounced:   regexp.MustCompile(`(?i)^(\d{4}\b|not (officially )?announced|exp\. announcement)`),
+	ColumnLaunchStatus:      regexp.MustCompile(`(?i)^(available|discontinued|cancelled|coming soon)`),
+	ColumnBodyDimensions:    regexp.MustCompile(`(?i)\d\s*(mm)?\s*x\s*[\d.]+\s*(mm)?\s*x\s*[\d.]+.*mm|mm thickness`),
+	ColumnBodyWeight:        regexp.MustCompile(`(?i)^\d+(\.\d+)?\s*g\b`),
+	ColumnBodySim:           regexp.MustCompile(`(?i)\b(e?sim|mini-sim|micro-sim|nano-sim)\b|^(yes|no)$`),
+	ColumnDisplayType:       regexp.MustCompile(`(?i)\bcolors\b|touchscreen|\b(ips|lcd|oled|amoled|tft|tfd|c?stn|monochrome|gr[ae]yscale)\b`),
+	ColumnDisplaySize:       regexp.MustCompile(`(?i)^\d+(\.\d+)?\s*inch`),
+	ColumnDisplayResolution: regexp.MustCompile(`(?i)\b(pixels|lines|chars)\b`),
+	ColumnFeaturesSensors:   regexp.MustCompile(`(?i)accelerometer|proximity|compass|gyro|fingerprint|barometer`),
+	ColumnPlatformOS:        regexp.MustCompile(`(?i)^(android|microsoft|windows|symbian|palm|linux|kaios|ios|blackberry|tizen)\b`),
+}
+
+// numericColumns are the columns in which a bare number, such as the year
+// "2019", is a plausible value rather than a placeholder.
+var numericColumns = map[string]bool{
+	ColumnLaunchAnnounced: true,
+	ColumnBodyWeight:      true,
+	ColumnDisplaySize:     true,
+}
+
+// RowCheck is the outcome of checking a cells.csv record for plausibility.
+type RowCheck struct {
+	// fields of the record with placeholders cleared and shifted values
+	// moved back into their columns
+	Record []string
+	// columns whose placeholder value was cleared, in file order
+	Placeholders []string
+	// number of columns the values were moved to the right to realign
+	// them, negative if they were moved left, 0 if the row was not shifted
+	Shift int
+	// reason the row cannot be trusted, nil if it can be loaded
+	Err error
+}
+
+// Repaired reports whether the record had to be changed to be loaded.
+func (c RowCheck) Repaired() bool {
+	return len(c.Placeholders) > 0 || c.Shift != 0
+}
+
+// CheckRecord looks for placeholder values and shifted columns in a cells.csv
+// record. Placeholders are cleared. If values of other columns are found, the
+// row is realigned when moving the values from the first misplaced one onward
+// by the same number of columns puts each of them in a column it fits, and
+// drops nothing but empty or unrecognised values; otherwise the returned
+// RowCheck carries ErrShiftedRow. The oem and model columns are never moved.
+// The record may be shorter or longer than the header: missing fields are
+// empty, and fields past the last column are values shifted off the end of
+// the row, which are moved back or dropped like any other value. A realigned
+// record has exactly as many fields as the header.
+func (h *Header) CheckRecord(line []string) RowCheck {
+	check := RowCheck{Record: append([]string(nil), line...)}
+	columns := h.dataColumns()
+
+	for _, name := range columns {
+		i := h.index[name]
+		if i >= len(check.Record) {
+			continue
+		}
+		value := strings.TrimSpace(check.Record[i])
+		if isPlaceholder(value) && !(numericColumns[name] && value[0] >= '0' && value[0] <= '9') {
+			check.Record[i] = ""
+			check.Placeholders = append(check.Placeholders, name)
+		}
+	}
+
+	values := make([]string, len(columns))
+	for j, name := range columns {
+		values[j] = h.Value(check.Record, name)
+	}
+	width := h.width()
+	if len(check.Record) > width {
+		values = append(values, check.Record[width:]...)
+	}
+
+	from, shift := findShift(columns, values)
+	if from < 0 {
+		return check
+	}
+
+	realigned, ok := realign(columns, values, from, shift)
+	if ok {
+		if again, _ := findShift(columns, realigned); again >= 0 {
+			ok = false
+		}
+	}
+	if !ok {
+		check.Err = fmt.Errorf("%w: %s", ErrShiftedRow, describeMisplaced(columns, values))
+		return check
+	}
+
+	if len(check.Record) > width {
+		check.Record = check.Record[:width]
+	}
+	for j, name := range columns {
+		if i := h.index[name]; i < len(check.Record) {
+			check.Record[i] = realigned[j]
+		} else if realigned[j] != "" {
+			check.Record = append(check.Record, make([]string, i+1-len(check.Record))...)
+			check.Record[i] = realigned[j]
+		}
+	}
+	check.Shift = shift
+	return check
+}
+
+// dataColumns returns the required columns other than oem and model, in the
+// order they appear in the file.
+func (h *Header) dataColumns() []string {
+	columns := make([]string, 0, len(RequiredColumns)-2)
+	for _, name := range RequiredColumns {
+		if name != ColumnOEM && name != ColumnModel {
+			columns = append(columns, name)
+		}
+	}
+	sort.Slice(columns, func(i, j int) bool { return h.index[columns[i]] < h.index[columns[j]] })
+	return columns
+}
+
+// width returns the number of fields of a record laid out as the header.
+func (h *Header) width() int {
+	width := 0
+	for _, i := range h.index {
+		if i+1 > width {
+			width = i + 1
+		}
+	}
+	return width
+}
+
+// fits reports whether value plausibly belongs in the named column.
+func fits(name, value string) bool {
+	return columnSignatures[name].MatchString(strings.TrimSpace(value))
+}
+
+// inPlace reports whether values[j] is empty or fits its own column. Values
+// past the last column have no column of their own, so only empty ones are
+// in place.
+func inPlace(columns []string, j int, value string) bool {
+	if strings.TrimSpace(value) == "" || value == "-" {
+		return true
+	}
+	return j < len(columns) && fits(columns[j], value)
+}
+
+// findShift returns the index of the first value that clearly belongs in
+// another column, or -1 if there is none, and the single offset by which
+// every such value would have to move to reach a column it fits. The offset
+// is 0 if no single one works. values may run past the last of columns.
+func findShift(columns, values []string) (int, int) {
+	from := -1
+	var offsets map[int]bool
+	for j, value := range values {
+		if inPlace(columns, j, value) {
+			continue
+		}
+		candidates := make(map[int]bool)
+		for k, name := range columns {
+			if k != j && fits(name, value) {
+				candidates[k-j] = true
+			}
+		}
+		if len(candidates) == 0 {
+			// unrecognised values say nothing about the layout
+			continue
+		}
+		if from < 0 {
+			from = j
+		}
+		if offsets == nil {
+			offsets = candidates
+			continue
+		}
+		for offset := range offsets {
+			if !candidates[offset] {
+				delete(offsets, offset)
+			}
+		}
+	}
+	if len(offsets) != 1 {
+		return from, 0
+	}
+	for offset := range offsets {
+		return from, offset
+	}
+	return from, 0
+}
+
+// realign moves the values from index from onward by shift columns and
+// returns one value per column. Values moved off the end of the row, and
+// values overwritten when moving left, must be empty or fit no column;
+// otherwise it reports false.
+func realign(columns, values []string, from, shift int) ([]string, bool) {
+	if shift == 0 || from+shift < 0 {
+		return nil, false
+	}
+	realigned := make([]string, len(columns))
+	copy(realigned, values[:from])
+	for j := from + shift; j < from; j++ {
+		if recognised(columns, values[j]) {
+			return nil, false
+		}
+		if j < len(realigned) {
+			realigned[j] = ""
+		}
+	}
+	for j := from; j < len(values); j++ {
+		k := j + shift
+		if k >= len(realigned) {
+			if recognised(columns, values[j]) {
+				return nil, false
+			}
+			continue
+		}
+		realigned[k] = values[j]
+	}
+	return realigned, true
+}
+
+// recognised reports whether value fits any of the columns.
+func recognised(columns []string, value string) bool {
+	for _, name := range columns {
+		if fits(name, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeMisplaced lists the columns holding values that belong elsewhere.
+func describeMisplaced(columns, values []string) string {
+	var names []string
+	for j, value := range values {
+		if inPlace(columns, j, value) {
+			continue
+		}
+		for k, name := range columns {
+			if k != j && fits(name, value) {
+				field := "an extra field"
+				if j < len(columns) {
+					field = columns[j]
+				}
+				names = append(names, fmt.Sprintf("%s holds %s", field, name))
+				break
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
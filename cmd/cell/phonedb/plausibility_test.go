@@ -0,0 +1,154 @@
+package phonedb
+
+import (
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckRecord(t *testing.T) {
+	header, err := NewHeader(RequiredColumns)
+	if err != nil {
+		t.Fatalf("NewHeader() error = %v", err)
+	}
+
+	pixel := []string{"Google", "Pixel 4 XL", "2019, October 15", "Available. Released 2019, October 22",
+		"160.4 x 75.1 x 8.2 mm", "193 g (6.81 oz)", "Nano-SIM, eSIM", "OLED capacitive touchscreen, 16M colors",
+		"6.3 inches", "1440 x 3040 pixels", "Accelerometer, gyro", "Android 10"}
+
+	tests := []struct {
+		name             string
+		line             []string
+		wantRecord       []string
+		wantPlaceholders []string
+		wantShift        int
+		wantErr          bool
+	}{
+		{
+			name:       "clean row",
+			line:       pixel,
+			wantRecord: pixel,
+		},
+		{
+			name:             "placeholders",
+			line:             []string{"LG", "LG-200", "V1", "Discontinued", "V1", "80 g", "Mini-SIM", "Monochrome graphic", "", "128 x 64 pixels", "V1", "12.2"},
+			wantRecord:       []string{"LG", "LG-200", "", "Discontinued", "", "80 g", "Mini-SIM", "Monochrome graphic", "", "128 x 64 pixels", "", ""},
+			wantPlaceholders: []string{ColumnLaunchAnnounced, ColumnBodyDimensions, ColumnFeaturesSensors, ColumnPlatformOS},
+		},
+		{
+			name: "missing cell shifts values left",
+			line: []string{"Google", "Pixel 4 XL", "2019, October 15", "160.4 x 75.1 x 8.2 mm", "193 g (6.81 oz)",
+				"Nano-SIM, eSIM", "OLED capacitive touchscreen, 16M colors", "6.3 inches", "1440 x 3040 pixels",
+				"Accelerometer, gyro", "Android 10", ""},
+			wantRecord: []string{"Google", "Pixel 4 XL", "2019, October 15", "", "160.4 x 75.1 x 8.2 mm", "193 g (6.81 oz)",
+				"Nano-SIM, eSIM", "OLED capacitive touchscreen, 16M colors", "6.3 inches", "1440 x 3040 pixels",
+				"Accelerometer, gyro", "Android 10"},
+			wantShift: 1,
+		},
+		{
+			name: "extra cell shifts values right",
+			line: []string{"Google", "Pixel 4 XL", "2019, October 15", "Available", "160.4 x 75.1 x 8.2 mm", "(6.81 oz)",
+				"193 g", "Nano-SIM, eSIM", "OLED capacitive touchscreen, 16M colors", "6.3 inches", "1440 x 3040 pixels",
+				"Accelerometer, gyro"},
+			wantRecord: []string{"Google", "Pixel 4 XL", "2019, October 15", "Available", "160.4 x 75.1 x 8.2 mm",
+				"193 g", "Nano-SIM, eSIM", "OLED capacitive touchscreen, 16M colors", "6.3 inches", "1440 x 3040 pixels",
+				"Accelerometer, gyro", ""},
+			wantShift: -1,
+		},
+		{
+			name: "values in unrelated columns",
+			line: []string{"Google", "Pixel 4 XL", "2019, October 15", "Available", "Android 10", "193 g (6.81 oz)",
+				"Nano-SIM", "6.3 inches", "OLED, 16M colors", "1440 x 3040 pixels", "Accelerometer", ""},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := header.CheckRecord(tt.line)
+			if tt.wantErr {
+				if !errors.Is(got.Err, ErrShiftedRow) {
+					t.Errorf("CheckRecord() error = %v; want %v", got.Err, ErrShiftedRow)
+				}
+				return
+			}
+			if got.Err != nil {
+				t.Fatalf("CheckRecord() error = %v", got.Err)
+			}
+			if !reflect.DeepEqual(got.Record, tt.wantRecord) {
+				t.Errorf("CheckRecord().Record = %q; want %q", got.Record, tt.wantRecord)
+			}
+			if !reflect.DeepEqual(got.Placeholders, tt.wantPlaceholders) {
+				t.Errorf("CheckRecord().Placeholders = %v; want %v", got.Placeholders, tt.wantPlaceholders)
+			}
+			if got.Shift != tt.wantShift {
+				t.Errorf("CheckRecord().Shift = %d; want %d", got.Shift, tt.wantShift)
+			}
+		})
+	}
+}
+
+func TestLoadQuarantinesShiftedRows(t *testing.T) {
+	data := strings.Join(RequiredColumns, ",") + "\n" +
+		"Google,Pixel 4 XL,2019,Available,Android 10,193 g,Nano-SIM,6.3 inches,OLED,1440 x 3040 pixels,Accelerometer,\n" +
+		"Benefon,Vega,1999,Discontinued,145 x 56 x 23 mm,190 g,Mini-SIM,Monochrome graphic,,6 lines,V1,\n"
+
+	catalog, report, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if catalog.Len() != 1 || catalog.Get("Benefon", "Vega") == nil {
+		t.Errorf("Load() loaded %v; want only Benefon Vega", catalog.Cells())
+	}
+	if len(report.Quarantined) != 1 || report.Quarantined[0].Line != 2 || !errors.Is(report.Quarantined[0], ErrShiftedRow) {
+		t.Errorf("LoadReport.Quarantined = %v; want line 2 with %v", report.Quarantined, ErrShiftedRow)
+	}
+	if len(report.Repaired) != 1 || report.Repaired[0].Line != 3 {
+		t.Errorf("LoadReport.Repaired = %v; want line 3", report.Repaired)
+	}
+}
+
+func TestLoadRealignsRowsWithTheWrongNumberOfFields(t *testing.T) {
+	data := strings.Join(RequiredColumns, ",") + "\n" +
+		// an unquoted comma splits the weight, pushing the rest of the row
+		// one field past the header
+		"Google,Pixel 4 XL,2019,Available,160.4 x 75.1 x 8.2 mm,(6.81 oz),193 g,Nano-SIM,OLED,6.3 inches,1440 x 3040 pixels,Accelerometer,Android 10\n" +
+		// the dimensions are missing, pulling the rest of the row one field
+		// short of the header
+		"Samsung,Galaxy S10,2019,Available,157 g,Nano-SIM,AMOLED,6.1 inches,1440 x 3040 pixels,Accelerometer,Android 9\n" +
+		// too short to be a shifted row
+		"Google,Pixel 2,2017\n"
+
+	catalog, report, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Line != 4 || !errors.Is(report.Errors[0], csv.ErrFieldCount) {
+		t.Errorf("LoadReport.Errors = %v; want line 4 with %v", report.Errors, csv.ErrFieldCount)
+	}
+	var shifts []int
+	for _, repair := range report.Repaired {
+		shifts = append(shifts, repair.Shift)
+	}
+	if want := []int{-1, 1}; !reflect.DeepEqual(shifts, want) {
+		t.Errorf("LoadReport.Repaired shifts = %v; want %v", shifts, want)
+	}
+
+	pixel := catalog.Get("Google", "Pixel 4 XL")
+	if pixel == nil {
+		t.Fatalf("Load() did not load the Pixel 4 XL")
+	}
+	if pixel.BodyWeight() != Some[float64](193) || pixel.PlatformOS().Value().Family != "Android" {
+		t.Errorf("Pixel 4 XL weight = %v, OS = %v; want 193 g and Android", pixel.BodyWeight(), pixel.PlatformOS())
+	}
+	galaxy := catalog.Get("Samsung", "Galaxy S10")
+	if galaxy == nil {
+		t.Fatalf("Load() did not load the Galaxy S10")
+	}
+	if galaxy.BodyDimensions().Known() || galaxy.BodyWeight() != Some[float64](157) || galaxy.PlatformOS().Value().Family != "Android" {
+		t.Errorf("Galaxy S10 dimensions = %v, weight = %v, OS = %v; want unknown, 157 g and Android",
+			galaxy.BodyDimensions(), galaxy.BodyWeight(), galaxy.PlatformOS())
+	}
+}
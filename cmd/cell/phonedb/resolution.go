@@ -0,0 +1,148 @@
+package phonedb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Resolution describes a phone's display resolution. Graphic displays are
+// measured in pixels, while old text displays are measured in lines or in
+// rows and columns of characters. A value that is not known is zero.
+type Resolution struct {
+	// horizontal pixels, as listed first in the dataset
+	Width int
+	// vertical pixels
+	Height int
+	// declared aspect ratio, such as 20 and 9 for "20:9 ratio"
+	RatioWidth  float64
+	RatioHeight float64
+	// declared pixel density in pixels per inch
+	PPI int
+	// lines of text the display can show
+	Lines int
+	// rows and columns of characters on a character display
+	Rows    int
+	Columns int
+}
+
+var (
+	// "1080 x 2400 pixels"
+	pixelsRe = regexp.MustCompile(`(\d+)\s*x\s*(\d+)\s*pixels`)
+	// "20:9 ratio"
+	ratioRe = regexp.MustCompile(`(\d+(?:\.\d+)?):(\d+(?:\.\d+)?)\s*ratio`)
+	// "(~418 ppi density)"
+	ppiRe = regexp.MustCompile(`(\d+)\s*ppi`)
+	// "6 lines" or "up to 7 lines"
+	linesRe = regexp.MustCompile(`(\d+)\s*lines`)
+	// "4 x 12 chars" or "5 x 13 to 25 chars"
+	charsRe = regexp.MustCompile(`(\d+)\s*x\s*(\d+)(?:\s*to\s*(\d+))?\s*chars`)
+)
+
+// ParseResolution extracts the pixel dimensions, declared aspect ratio, pixel
+// density and line or character counts from a display_resolution string. If
+// none of them are found it returns nil.
+func ParseResolution(resStr string) *Resolution {
+	var res Resolution
+	found := false
+
+	if match := pixelsRe.FindStringSubmatch(resStr); match != nil {
+		res.Width, _ = strconv.Atoi(match[1])
+		res.Height, _ = strconv.Atoi(match[2])
+		found = true
+	}
+
+	if match := ratioRe.FindStringSubmatch(resStr); match != nil {
+		res.RatioWidth, _ = strconv.ParseFloat(match[1], 64)
+		res.RatioHeight, _ = strconv.ParseFloat(match[2], 64)
+		found = true
+	}
+
+	if match := ppiRe.FindStringSubmatch(resStr); match != nil {
+		res.PPI, _ = strconv.Atoi(match[1])
+		found = true
+	}
+
+	if match := linesRe.FindStringSubmatch(resStr); match != nil {
+		res.Lines, _ = strconv.Atoi(match[1])
+		found = true
+	}
+
+	if match := charsRe.FindStringSubmatch(resStr); match != nil {
+		res.Rows, _ = strconv.Atoi(match[1])
+		// "13 to 25 chars" keeps the largest number of columns
+		columns := match[2]
+		if match[3] != "" {
+			columns = match[3]
+		}
+		res.Columns, _ = strconv.Atoi(columns)
+		found = true
+	}
+
+	if !found {
+		return nil
+	}
+	return &res
+}
+
+// HasPixels reports whether the pixel dimensions are known.
+func (r Resolution) HasPixels() bool {
+	return r.Width > 0 && r.Height > 0
+}
+
+// Pixels returns the total number of pixels, or 0 if unknown.
+func (r Resolution) Pixels() int {
+	return r.Width * r.Height
+}
+
+// AspectRatio returns the ratio of the long side to the short side of the
+// display. The declared ratio is preferred, otherwise it is computed from
+// the pixel dimensions. It returns 0 if neither is known.
+func (r Resolution) AspectRatio() float64 {
+	long, short := r.RatioWidth, r.RatioHeight
+	if long == 0 || short == 0 {
+		long, short = float64(r.Width), float64(r.Height)
+	}
+	if short > long {
+		long, short = short, long
+	}
+	if short == 0 {
+		return 0
+	}
+	return long / short
+}
+
+// Label returns the pixel dimensions as "W x H", or an empty string if they
+// are not known. It is used to group phones by resolution.
+func (r Resolution) Label() string {
+	if !r.HasPixels() {
+		return ""
+	}
+	return fmt.Sprintf("%d x %d", r.Width, r.Height)
+}
+
+// String implements the Stringer interface for Resolution.
+func (r Resolution) String() string {
+	var parts []string
+	if r.HasPixels() {
+		parts = append(parts, r.Label()+" pixels")
+	}
+	if r.Lines > 0 {
+		parts = append(parts, fmt.Sprintf("%d lines", r.Lines))
+	}
+	if r.Rows > 0 && r.Columns > 0 {
+		parts = append(parts, fmt.Sprintf("%d x %d chars", r.Rows, r.Columns))
+	}
+	if r.RatioWidth > 0 && r.RatioHeight > 0 {
+		parts = append(parts, fmt.Sprintf("%g:%g ratio", r.RatioWidth, r.RatioHeight))
+	}
+	s := strings.Join(parts, ", ")
+	if r.PPI > 0 {
+		s += fmt.Sprintf(" (~%d ppi density)", r.PPI)
+	}
+	if s == "" {
+		return "-"
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,59 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseResolution(t *testing.T) {
+	tests := []struct {
+		input string
+		want  *Resolution
+	}{
+		{"1080 x 2400 pixels, 20:9 ratio (~418 ppi density)", &Resolution{Width: 1080, Height: 2400, RatioWidth: 20, RatioHeight: 9, PPI: 418}},
+		{"128 x 160 pixels, 11 lines", &Resolution{Width: 128, Height: 160, Lines: 11}},
+		{"96 x 32 pixels, 4 x 12 chars", &Resolution{Width: 96, Height: 32, Rows: 4, Columns: 12}},
+		{"5 x 13 to 25 chars", &Resolution{Rows: 5, Columns: 25}},
+		{"up to 7 lines", &Resolution{Lines: 7}},
+		{"6 lines", &Resolution{Lines: 6}},
+		{"-", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		got := ParseResolution(test.input)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseResolution(%q) = %+v, want %+v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestResolutionAspectRatio(t *testing.T) {
+	tests := []struct {
+		res  Resolution
+		want float64
+	}{
+		{Resolution{Width: 1080, Height: 2400, RatioWidth: 20, RatioHeight: 9}, 20.0 / 9},
+		{Resolution{Width: 320, Height: 240}, 4.0 / 3},
+		{Resolution{Lines: 6}, 0},
+	}
+
+	for _, test := range tests {
+		if got := test.res.AspectRatio(); !almostEqual(got, test.want, 1e-9) {
+			t.Errorf("%+v.AspectRatio() = %v, want %v", test.res, got, test.want)
+		}
+	}
+}
+
+func TestPixelDensity(t *testing.T) {
+	declared := &Cell{displayResolution: Some(Resolution{Width: 1080, Height: 2400, PPI: 418}), displaySize: Some(6.3)}
+	if got := declared.PixelDensity(); got != Some(418.0) {
+		t.Errorf("PixelDensity() = %v, want 418", got)
+	}
+
+	computed := &Cell{displayResolution: Some(Resolution{Width: 300, Height: 400}), displaySize: Some(5.0)}
+	if got := computed.PixelDensity(); got != Some(100.0) {
+		t.Errorf("PixelDensity() = %v, want 100", got)
+	}
+}
@@ -0,0 +1,630 @@
+package phonedb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity is how serious a rule violation is.
+type Severity int
+
+const (
+	// SeverityInfo marks a violation worth knowing about.
+	SeverityInfo Severity = iota
+	// SeverityWarning marks a violation that probably needs fixing.
+	SeverityWarning
+	// SeverityError marks a violation that makes the data unusable.
+	SeverityError
+)
+
+// String implements the Stringer interface for Severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// ParseSeverity returns the Severity named by s, which is "info", "warning"
+// or "error".
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "info":
+		return SeverityInfo, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return 0, fmt.Errorf("unknown severity %q", s)
+}
+
+// MarshalText implements encoding.TextMarshaler for Severity.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for Severity.
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// fieldValue is the value of a rule field for one phone.
+type fieldValue struct {
+	num   float64
+	str   string
+	known bool
+}
+
+// ruleField describes a field rules can refer to.
+type ruleField struct {
+	// whether the field holds a number rather than text
+	numeric bool
+	get     func(*Cell) fieldValue
+}
+
+// number returns a known numeric field value.
+func number(n float64) fieldValue { return fieldValue{num: n, known: true} }
+
+// text returns a known text field value.
+func text(s string) fieldValue { return fieldValue{str: s, known: true} }
+
+// ruleFields are the fields rules can refer to, by name.
+var ruleFields = map[string]ruleField{
+	"oem":   {get: func(c *Cell) fieldValue { return text(c.oem) }},
+	"model": {get: func(c *Cell) fieldValue { return text(c.model) }},
+	"launch_announced": {numeric: true, get: func(c *Cell) fieldValue {
+		if !c.launch.Announced.Known() {
+			return fieldValue{}
+		}
+		return number(float64(c.launch.Announced.Year))
+	}},
+	"launch_decade": {numeric: true, get: func(c *Cell) fieldValue {
+		if decade, ok := ByDecade(c); ok {
+			return number(float64(decade))
+		}
+		return fieldValue{}
+	}},
+	"launch_released": {numeric: true, get: func(c *Cell) fieldValue {
+		if !c.launch.Released.Known() {
+			return fieldValue{}
+		}
+		return number(float64(c.launch.Released.Year))
+	}},
+	"launch_status": {get: func(c *Cell) fieldValue {
+		if c.launch.Status == StatusUnknown {
+			return fieldValue{}
+		}
+		return text(c.launch.Status.String())
+	}},
+	"body_height": {numeric: true, get: dimension(func(d Dimensions) float64 { return d.Height })},
+	"body_width":  {numeric: true, get: dimension(func(d Dimensions) float64 { return d.Width })},
+	"body_depth":  {numeric: true, get: dimension(func(d Dimensions) float64 { return d.Depth })},
+	"body_weight": {numeric: true, get: func(c *Cell) fieldValue {
+		if w, ok := c.bodyWeight.Get(); ok {
+			return number(w)
+		}
+		return fieldValue{}
+	}},
+	"body_sim_slots": {numeric: true, get: func(c *Cell) fieldValue {
+		if sim, ok := c.bodySim.Get(); ok && sim.Slots > 0 {
+			return number(float64(sim.Slots))
+		}
+		return fieldValue{}
+	}},
+	"display_panel": {get: func(c *Cell) fieldValue {
+		if d, ok := c.displayType.Get(); ok && d.Panel != PanelUnknown {
+			return text(d.Panel.String())
+		}
+		return fieldValue{}
+	}},
+	"display_colors": {numeric: true, get: func(c *Cell) fieldValue {
+		if d, ok := c.displayType.Get(); ok && d.Colors > 0 {
+			return number(float64(d.Colors))
+		}
+		return fieldValue{}
+	}},
+	"display_size": {numeric: true, get: func(c *Cell) fieldValue {
+		if size, ok := c.displaySize.Get(); ok {
+			return number(size)
+		}
+		return fieldValue{}
+	}},
+	"display_ppi": {numeric: true, get: func(c *Cell) fieldValue {
+		if ppi, ok := c.PixelDensity().Get(); ok {
+			return number(ppi)
+		}
+		return fieldValue{}
+	}},
+	"sensor_count": {numeric: true, get: func(c *Cell) fieldValue {
+		if sensors, ok := c.featuresSensors.Get(); ok {
+			return number(float64(sensors.Len()))
+		}
+		return fieldValue{}
+	}},
+	"sensors": {get: func(c *Cell) fieldValue {
+		if sensors, ok := c.featuresSensors.Get(); ok {
+			return text(sensors.String())
+		}
+		return fieldValue{}
+	}},
+	"platform_os": {get: func(c *Cell) fieldValue {
+		if os, ok := c.platformOS.Get(); ok {
+			return text(os.Family)
+		}
+		return fieldValue{}
+	}},
+}
+
+// dimension returns a field getter for one of the body dimensions, which is
+// unknown when it is zero.
+func dimension(get func(Dimensions) float64) func(*Cell) fieldValue {
+	return func(c *Cell) fieldValue {
+		if d, ok := c.bodyDimensions.Get(); ok && get(d) > 0 {
+			return number(get(d))
+		}
+		return fieldValue{}
+	}
+}
+
+// Condition is a test on the fields of a phone. Exactly one form is used:
+//
+//	{"field": "display_size", "between": [1, 12]}
+//	{"field": "body_weight", "op": "<=", "value": 500}
+//	{"field": "display_panel", "op": "==", "value": "OLED"}
+//	{"field": "launch_announced", "op": "<=", "other": "launch_released"}
+//	{"field": "oem", "op": "in", "value": ["Samsung", "LG"]}
+//	{"field": "sensors", "op": "contains", "value": "fingerprint"}
+//	{"field": "model", "op": "matches", "value": "^Galaxy S\\d+$"}
+//	{"field": "display_colors", "known": true}
+//	{"all": [...]}, {"any": [...]}, {"not": {...}}
+//	{"if": {...}, "then": {...}}
+//
+// Text comparisons and "contains" ignore case; "matches" takes a regular
+// expression, which is case-sensitive unless it starts with (?i). A
+// comparison involving an unknown field is neither true nor false, and
+// neither is "all", "any" or "not" when the outcome depends on it; only a
+// condition that is false is a violation.
+type Condition struct {
+	Field   string      `json:"field,omitempty"`
+	Between []float64   `json:"between,omitempty"`
+	Op      string      `json:"op,omitempty"`
+	Value   any         `json:"value,omitempty"`
+	Other   string      `json:"other,omitempty"`
+	Known   *bool       `json:"known,omitempty"`
+	All     []Condition `json:"all,omitempty"`
+	Any     []Condition `json:"any,omitempty"`
+	Not     *Condition  `json:"not,omitempty"`
+	If      *Condition  `json:"if,omitempty"`
+	Then    *Condition  `json:"then,omitempty"`
+
+	// compiled Value of a "matches" condition, set by validate
+	re *regexp.Regexp
+}
+
+// truth is the outcome of a Condition: true, false or unknown.
+type truth int
+
+const (
+	truthUnknown truth = iota
+	truthFalse
+	truthTrue
+)
+
+// truthOf converts a bool to a truth.
+func truthOf(b bool) truth {
+	if b {
+		return truthTrue
+	}
+	return truthFalse
+}
+
+// validate reports the first problem with the condition, such as an unknown
+// field or a missing operand.
+func (c *Condition) validate() error {
+	forms := 0
+	if c.Field != "" {
+		forms++
+	}
+	for _, used := range []bool{c.All != nil, c.Any != nil, c.Not != nil, c.If != nil || c.Then != nil} {
+		if used {
+			forms++
+		}
+	}
+	if forms != 1 {
+		return errors.New("condition must have exactly one of field, all, any, not or if/then")
+	}
+
+	switch {
+	case c.All != nil || c.Any != nil:
+		for i := range c.All {
+			if err := c.All[i].validate(); err != nil {
+				return err
+			}
+		}
+		for i := range c.Any {
+			if err := c.Any[i].validate(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case c.Not != nil:
+		return c.Not.validate()
+	case c.If != nil || c.Then != nil:
+		if c.If == nil || c.Then == nil {
+			return errors.New("if needs then")
+		}
+		if err := c.If.validate(); err != nil {
+			return err
+		}
+		return c.Then.validate()
+	}
+
+	field, ok := ruleFields[c.Field]
+	if !ok {
+		return fmt.Errorf("unknown field %q", c.Field)
+	}
+	tests := 0
+	for _, used := range []bool{c.Between != nil, c.Op != "", c.Known != nil} {
+		if used {
+			tests++
+		}
+	}
+	if tests != 1 {
+		return fmt.Errorf("field %q needs exactly one of between, op or known", c.Field)
+	}
+
+	switch {
+	case c.Between != nil:
+		if len(c.Between) != 2 || !field.numeric {
+			return fmt.Errorf("between needs two numbers and a numeric field, not %q", c.Field)
+		}
+		if c.Between[0] > c.Between[1] {
+			return fmt.Errorf("between needs the lower bound first, not %v", c.Between)
+		}
+	case c.Op != "":
+		if !validOps[c.Op] {
+			return fmt.Errorf("unknown op %q", c.Op)
+		}
+		if (c.Value == nil) == (c.Other == "") {
+			return fmt.Errorf("op %q needs exactly one of value or other", c.Op)
+		}
+		if c.Other != "" {
+			other, ok := ruleFields[c.Other]
+			if !ok {
+				return fmt.Errorf("unknown field %q", c.Other)
+			}
+			if other.numeric != field.numeric {
+				return fmt.Errorf("cannot compare %q with %q", c.Field, c.Other)
+			}
+			if !orderedOps[c.Op] && c.Op != "==" && c.Op != "!=" {
+				return fmt.Errorf("op %q needs a value, not another field", c.Op)
+			}
+			if !field.numeric && orderedOps[c.Op] {
+				return fmt.Errorf("text can only be compared with == or !=")
+			}
+			return nil
+		}
+		switch c.Op {
+		case "in":
+			values, ok := c.Value.([]any)
+			if !ok || len(values) == 0 {
+				return fmt.Errorf("op %q needs a list of values", c.Op)
+			}
+			for _, v := range values {
+				if err := checkValue(c.Field, field, v); err != nil {
+					return err
+				}
+			}
+			return nil
+		case "contains", "matches":
+			pattern, ok := c.Value.(string)
+			if !ok || field.numeric {
+				return fmt.Errorf("op %q needs a text field and text value, not %q", c.Op, c.Field)
+			}
+			if c.Op == "matches" {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return err
+				}
+				c.re = re
+			}
+			return nil
+		}
+		if err := checkValue(c.Field, field, c.Value); err != nil {
+			return err
+		}
+		if !field.numeric && orderedOps[c.Op] {
+			return fmt.Errorf("text can only be compared with == or !=")
+		}
+	}
+	return nil
+}
+
+// checkValue reports whether value has the type of the named field.
+func checkValue(name string, field ruleField, value any) error {
+	switch value.(type) {
+	case float64:
+		if !field.numeric {
+			return fmt.Errorf("field %q is text, not a number", name)
+		}
+	case string:
+		if field.numeric {
+			return fmt.Errorf("field %q is a number, not text", name)
+		}
+	default:
+		return fmt.Errorf("value of %q must be a number or text", name)
+	}
+	return nil
+}
+
+// validOps are the operators a Condition can use.
+var validOps = map[string]bool{
+	"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"in": true, "contains": true, "matches": true,
+}
+
+// orderedOps are the operators that only apply to numbers.
+var orderedOps = map[string]bool{"<": true, "<=": true, ">": true, ">=": true}
+
+// eval evaluates the condition against cell.
+func (c *Condition) eval(cell *Cell) truth {
+	switch {
+	case c.All != nil:
+		result := truthTrue
+		for i := range c.All {
+			switch c.All[i].eval(cell) {
+			case truthFalse:
+				return truthFalse
+			case truthUnknown:
+				result = truthUnknown
+			}
+		}
+		return result
+	case c.Any != nil:
+		result := truthFalse
+		for i := range c.Any {
+			switch c.Any[i].eval(cell) {
+			case truthTrue:
+				return truthTrue
+			case truthUnknown:
+				result = truthUnknown
+			}
+		}
+		return result
+	case c.Not != nil:
+		switch c.Not.eval(cell) {
+		case truthTrue:
+			return truthFalse
+		case truthFalse:
+			return truthTrue
+		}
+		return truthUnknown
+	case c.If != nil:
+		// "if a then b" is "not a or b"
+		switch c.If.eval(cell) {
+		case truthFalse:
+			return truthTrue
+		case truthTrue:
+			return c.Then.eval(cell)
+		}
+		if c.Then.eval(cell) == truthTrue {
+			return truthTrue
+		}
+		return truthUnknown
+	}
+
+	field := ruleFields[c.Field]
+	value := field.get(cell)
+	if c.Known != nil {
+		return truthOf(value.known == *c.Known)
+	}
+	if !value.known {
+		return truthUnknown
+	}
+
+	if c.Between != nil {
+		return truthOf(value.num >= c.Between[0] && value.num <= c.Between[1])
+	}
+
+	switch c.Op {
+	case "in":
+		for _, v := range c.Value.([]any) {
+			if equalValue(field, value, v) {
+				return truthTrue
+			}
+		}
+		return truthFalse
+	case "contains":
+		return truthOf(strings.Contains(strings.ToLower(value.str), strings.ToLower(c.Value.(string))))
+	case "matches":
+		re := c.re
+		if re == nil {
+			// the condition was built without being validated
+			var err error
+			if re, err = regexp.Compile(c.Value.(string)); err != nil {
+				return truthUnknown
+			}
+		}
+		return truthOf(re.MatchString(value.str))
+	}
+
+	var other fieldValue
+	if c.Other != "" {
+		other = ruleFields[c.Other].get(cell)
+		if !other.known {
+			return truthUnknown
+		}
+	} else if n, ok := c.Value.(float64); ok {
+		other = number(n)
+	} else {
+		other = text(c.Value.(string))
+	}
+
+	if !field.numeric {
+		equal := strings.EqualFold(value.str, other.str)
+		return truthOf(equal == (c.Op == "=="))
+	}
+	switch c.Op {
+	case "==":
+		return truthOf(value.num == other.num)
+	case "!=":
+		return truthOf(value.num != other.num)
+	case "<":
+		return truthOf(value.num < other.num)
+	case "<=":
+		return truthOf(value.num <= other.num)
+	case ">":
+		return truthOf(value.num > other.num)
+	default:
+		return truthOf(value.num >= other.num)
+	}
+}
+
+// equalValue reports whether a known field value equals v, a number or text
+// taken from a Condition. Text is compared ignoring case.
+func equalValue(field ruleField, value fieldValue, v any) bool {
+	if field.numeric {
+		n, ok := v.(float64)
+		return ok && value.num == n
+	}
+	s, ok := v.(string)
+	return ok && strings.EqualFold(value.str, s)
+}
+
+// fields appends the names of the fields the condition refers to to names,
+// without duplicates.
+func (c *Condition) fields(names []string) []string {
+	add := func(name string) {
+		for _, have := range names {
+			if have == name {
+				return
+			}
+		}
+		names = append(names, name)
+	}
+	if c.Field != "" {
+		add(c.Field)
+	}
+	if c.Other != "" {
+		add(c.Other)
+	}
+	for _, sub := range [][]Condition{c.All, c.Any} {
+		for i := range sub {
+			names = sub[i].fields(names)
+		}
+	}
+	for _, sub := range []*Condition{c.Not, c.If, c.Then} {
+		if sub != nil {
+			names = sub.fields(names)
+		}
+	}
+	return names
+}
+
+// Rule is a named Condition every phone is expected to meet.
+type Rule struct {
+	Name     string    `json:"name"`
+	Severity Severity  `json:"severity"`
+	Check    Condition `json:"check"`
+}
+
+// RuleSet is a list of rules, as read from a rules file.
+type RuleSet struct {
+	Rules []Rule `json:"rules"`
+}
+
+// ReadRules decodes a RuleSet from JSON such as
+//
+//	{"rules": [
+//	  {"name": "display size", "severity": "warning",
+//	   "check": {"field": "display_size", "between": [1, 12]}}
+//	]}
+//
+// Unknown keys, fields and operators are rejected.
+func ReadRules(r io.Reader) (*RuleSet, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	var rules RuleSet
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("read rules: %w", err)
+	}
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+		if rule.Name == "" {
+			return nil, fmt.Errorf("read rules: rule %d has no name", i+1)
+		}
+		if err := rule.Check.validate(); err != nil {
+			return nil, fmt.Errorf("read rules: rule %q: %w", rule.Name, err)
+		}
+	}
+	return &rules, nil
+}
+
+// RuleViolation is a phone that does not meet a Rule.
+type RuleViolation struct {
+	Rule *Rule
+	Cell *Cell
+}
+
+// String implements the Stringer interface for RuleViolation, listing the
+// values of the fields the rule refers to.
+func (v RuleViolation) String() string {
+	var values []string
+	for _, name := range v.Rule.Check.fields(nil) {
+		value := ruleFields[name].get(v.Cell)
+		switch {
+		case !value.known:
+			values = append(values, name+"=-")
+		case ruleFields[name].numeric:
+			values = append(values, name+"="+strconv.FormatFloat(value.num, 'g', -1, 64))
+		default:
+			values = append(values, fmt.Sprintf("%s=%q", name, value.str))
+		}
+	}
+	return fmt.Sprintf("line %d: %s %s: %s %q (%s)", v.Cell.line, v.Cell.oem, v.Cell.model,
+		v.Rule.Severity, v.Rule.Name, strings.Join(values, ", "))
+}
+
+// Evaluate checks every phone in the catalog against every rule and returns
+// the violations ordered by line, then OEM and model, then rule order.
+func (rs *RuleSet) Evaluate(c *Catalog) []RuleViolation {
+	cells := make([]*Cell, 0, len(c.cells))
+	for _, cell := range c.cells {
+		cells = append(cells, cell)
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].line != cells[j].line {
+			return cells[i].line < cells[j].line
+		}
+		if cells[i].oem != cells[j].oem {
+			return cells[i].oem < cells[j].oem
+		}
+		return cells[i].model < cells[j].model
+	})
+
+	var violations []RuleViolation
+	for _, cell := range cells {
+		for i := range rs.Rules {
+			if rs.Rules[i].Check.eval(cell) == truthFalse {
+				violations = append(violations, RuleViolation{Rule: &rs.Rules[i], Cell: cell})
+			}
+		}
+	}
+	return violations
+}
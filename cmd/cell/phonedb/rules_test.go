@@ -0,0 +1,126 @@
+package phonedb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testRules = `{"rules": [
+	{"name": "display size", "severity": "warning",
+	 "check": {"field": "display_size", "between": [1, 12]}},
+	{"name": "launch order", "severity": "error",
+	 "check": {"field": "launch_announced", "op": "<=", "other": "launch_released"}},
+	{"name": "oled colors", "severity": "info",
+	 "check": {"if": {"field": "display_panel", "op": "==", "value": "oled"},
+	           "then": {"field": "display_colors", "known": true}}}
+]}`
+
+func TestEvaluateRules(t *testing.T) {
+	rules, err := ReadRules(strings.NewReader(testRules))
+	if err != nil {
+		t.Fatalf("ReadRules() error = %v", err)
+	}
+
+	year := func(y uint) Date { return Date{Year: y, Precision: PrecisionYear} }
+	cells := &Catalog{cells: map[string]*Cell{
+		"Apple-iPad": {oem: "Apple", model: "iPad", line: 4, displaySize: Some(12.9)},
+		"Nokia-N97": {oem: "Nokia", model: "N97", line: 2, displaySize: Some(3.5),
+			launch: LaunchInfo{Announced: year(2009), Released: year(2008)}},
+		"Google-Pixel": {oem: "Google", model: "Pixel", line: 3, displayType: Some(DisplayType{Panel: PanelOLED})},
+		// unknown fields break no rule
+		"Benefon-Vega": {oem: "Benefon", model: "Vega", line: 5, launch: LaunchInfo{Announced: year(1999)}},
+		"LG-G8":        {oem: "LG", model: "G8", line: 6, displayType: Some(DisplayType{Panel: PanelOLED, Colors: 16000000})},
+	}}
+
+	var got []string
+	for _, v := range rules.Evaluate(cells) {
+		got = append(got, v.String())
+	}
+	want := []string{
+		`line 2: Nokia N97: error "launch order" (launch_announced=2009, launch_released=2008)`,
+		`line 3: Google Pixel: info "oled colors" (display_panel="OLED", display_colors=-)`,
+		`line 4: Apple iPad: warning "display size" (display_size=12.9)`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rules.Evaluate() = %q; want %q", got, want)
+	}
+}
+
+// TestEvaluateRulesDecimals checks phones loaded from text, so a decimal in
+// the file must equal the same decimal in a rule and print as written.
+func TestEvaluateRulesDecimals(t *testing.T) {
+	rules, err := ReadRules(strings.NewReader(`{"rules": [
+		{"name": "tablet", "severity": "warning",
+		 "check": {"field": "display_size", "op": "<=", "value": 6.3}}
+	]}`))
+	if err != nil {
+		t.Fatalf("ReadRules() error = %v", err)
+	}
+	data := strings.Join(RequiredColumns, ",") + "\n" +
+		"Google,Pixel 4 XL,2019,Available,-,193 g,Nano-SIM,OLED,6.3 inches,-,Accelerometer,Android 10\n" +
+		"Google,Pixel 6 Pro,2021,Available,-,210 g,Nano-SIM,OLED,6.7 inches,-,Accelerometer,Android 12\n"
+	cells, _, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var got []string
+	for _, v := range rules.Evaluate(cells) {
+		got = append(got, v.String())
+	}
+	want := []string{`line 3: Google Pixel 6 Pro: warning "tablet" (display_size=6.7)`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rules.Evaluate() = %q; want %q", got, want)
+	}
+}
+
+func TestConditionLogic(t *testing.T) {
+	oled := &Cell{displayType: Some(DisplayType{Panel: PanelOLED})}
+	isOLED := Condition{Field: "display_panel", Op: "==", Value: "OLED"}
+	heavy := Condition{Field: "body_weight", Op: ">", Value: 500.0}
+
+	tests := []struct {
+		name string
+		cond Condition
+		want truth
+	}{
+		{"unknown field", heavy, truthUnknown},
+		{"not unknown", Condition{Not: &heavy}, truthUnknown},
+		{"all with false", Condition{All: []Condition{heavy, {Not: &isOLED}}}, truthFalse},
+		{"all with unknown", Condition{All: []Condition{heavy, isOLED}}, truthUnknown},
+		{"any with true", Condition{Any: []Condition{heavy, isOLED}}, truthTrue},
+		{"if unknown then true", Condition{If: &heavy, Then: &isOLED}, truthTrue},
+		{"if true then unknown", Condition{If: &isOLED, Then: &heavy}, truthUnknown},
+		{"in", Condition{Field: "display_panel", Op: "in", Value: []any{"lcd", "oled"}}, truthTrue},
+		{"contains", Condition{Field: "display_panel", Op: "contains", Value: "led"}, truthTrue},
+		{"matches", Condition{Field: "display_panel", Op: "matches", Value: "^AM"}, truthFalse},
+		{"in unknown", Condition{Field: "oem", Op: "in", Value: []any{"Google"}}, truthFalse},
+	}
+	for _, tt := range tests {
+		if got := tt.cond.eval(oled); got != tt.want {
+			t.Errorf("%s: eval() = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadRulesRejectsInvalidRules(t *testing.T) {
+	for _, input := range []string{
+		`{"rules": [{"name": "x", "check": {"field": "weight", "op": "<", "value": 1}}]}`,
+		`{"rules": [{"name": "x", "check": {"field": "body_weight", "op": "=<", "value": 1}}]}`,
+		`{"rules": [{"name": "x", "check": {"field": "body_weight", "op": "<", "value": "heavy"}}]}`,
+		`{"rules": [{"name": "x", "check": {"field": "oem", "between": [1, 2]}}]}`,
+		`{"rules": [{"name": "x", "check": {"field": "body_weight", "between": [1000, 10]}}]}`,
+		`{"rules": [{"name": "x", "check": {"if": {"field": "oem", "known": true}}}]}`,
+		`{"rules": [{"name": "x", "severity": "fatal", "check": {"field": "oem", "known": true}}]}`,
+		`{"rules": [{"check": {"field": "oem", "known": true}}]}`,
+		`{"rules": [{"name": "x", "check": {"field": "oem", "op": "in", "value": [1]}}]}`,
+		`{"rules": [{"name": "x", "check": {"field": "body_weight", "op": "contains", "value": "1"}}]}`,
+		`{"rules": [{"name": "x", "check": {"field": "model", "op": "matches", "value": "("}}]}`,
+		`{"rules": [{"name": "x", "check": {"field": "model", "op": "in", "other": "oem"}}]}`,
+	} {
+		if _, err := ReadRules(strings.NewReader(input)); err == nil {
+			t.Errorf("ReadRules(%s) error = nil; want an error", input)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package phonedb
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Sensor is the canonical name of a phone sensor.
+type Sensor string
+
+// Sensors found in the dataset. Names not listed here are kept in lower case
+// as they appear.
+const (
+	SensorAccelerometer   Sensor = "accelerometer"
+	SensorGyro            Sensor = "gyro"
+	SensorCompass         Sensor = "compass"
+	SensorProximity       Sensor = "proximity"
+	SensorBarometer       Sensor = "barometer"
+	SensorFingerprint     Sensor = "fingerprint"
+	SensorFaceRecognition Sensor = "face recognition"
+	SensorIrisScanner     Sensor = "iris scanner"
+	SensorHeartRate       Sensor = "heart rate"
+	SensorColorSpectrum   Sensor = "color spectrum"
+	SensorSpO2            Sensor = "spo2"
+	SensorUV              Sensor = "uv"
+	SensorAltimeter       Sensor = "altimeter"
+)
+
+// sensorAliases maps lower case names from features_sensors to the
+// canonical sensor they describe.
+var sensorAliases = map[string]Sensor{
+	"gyroscope":                 SensorGyro,
+	"baroceptor":                SensorBarometer,
+	"face id":                   SensorFaceRecognition,
+	"infrared face recognition": SensorFaceRecognition,
+	"uv sensor":                 SensorUV,
+}
+
+// Fingerprint describes a fingerprint reader. A detail that is not listed
+// is empty.
+type Fingerprint struct {
+	// where the reader is, such as "rear-mounted" or "under display"
+	Placement string
+	// how the reader works, such as "optical" or "ultrasonic"
+	Kind string
+}
+
+// Sensors is the set of sensors a phone has.
+type Sensors struct {
+	// canonical sensor names, sorted and without duplicates
+	List []Sensor
+	// details of the fingerprint reader, if the phone has one
+	Fingerprint Fingerprint
+}
+
+// a missing comma between two sensors, as in "compassFingerprint"
+var joinedRe = regexp.MustCompile(`([a-z])([A-Z][a-z]{2,})`)
+
+// ParseSensors decomposes a features_sensors string into a set of canonical
+// sensors. Commas inside parentheses do not separate sensors, so
+// "Fingerprint (under display, optical), accelerometer" is two sensors. If
+// the string is empty or holds only placeholder values it returns nil.
+func ParseSensors(sensorStr string) *Sensors {
+	sensorStr = joinedRe.ReplaceAllString(sensorStr, "$1, $2")
+
+	var sensors Sensors
+	seen := make(map[Sensor]bool)
+	for _, part := range splitOutsideParens(sensorStr) {
+		// drop qualifiers such as "compass - India only"
+		if i := strings.Index(part, " - "); i >= 0 {
+			part = part[:i]
+		}
+		part = strings.TrimSpace(part)
+		if part == "" || isPlaceholder(part) {
+			continue
+		}
+
+		name, details := part, ""
+		if i := strings.Index(part, "("); i >= 0 {
+			name = strings.TrimSpace(part[:i])
+			details = strings.Trim(part[i:], "()")
+		}
+
+		sensor := canonicalSensor(name)
+		if sensor == SensorFingerprint && details != "" {
+			sensors.Fingerprint = parseFingerprint(details)
+		}
+		if !seen[sensor] {
+			seen[sensor] = true
+			sensors.List = append(sensors.List, sensor)
+		}
+	}
+
+	if len(sensors.List) == 0 {
+		return nil
+	}
+	sort.Slice(sensors.List, func(i, j int) bool { return sensors.List[i] < sensors.List[j] })
+	return &sensors
+}
+
+// splitOutsideParens splits s on the commas that are not inside parentheses.
+func splitOutsideParens(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// canonicalSensor returns the canonical Sensor for a name from
+// features_sensors.
+func canonicalSensor(name string) Sensor {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	// "Dual fingerprint" is still a fingerprint reader
+	name = strings.TrimPrefix(name, "dual ")
+	if sensor, ok := sensorAliases[name]; ok {
+		return sensor
+	}
+	return Sensor(name)
+}
+
+// parseFingerprint reads the placement and kind of a fingerprint reader from
+// the details in parentheses, such as "under display, optical".
+func parseFingerprint(details string) Fingerprint {
+	var fingerprint Fingerprint
+	for _, detail := range strings.Split(details, ",") {
+		detail = strings.ToLower(strings.TrimSpace(detail))
+		switch {
+		case detail == "optical" || detail == "ultrasonic":
+			fingerprint.Kind = detail
+		case detail != "":
+			fingerprint.Placement = detail
+		}
+	}
+	return fingerprint
+}
+
+// Has reports whether the set contains sensor.
+func (s Sensors) Has(sensor Sensor) bool {
+	for _, have := range s.List {
+		if have == sensor {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of sensors in the set.
+func (s Sensors) Len() int {
+	return len(s.List)
+}
+
+// String implements the Stringer interface for Sensors.
+func (s Sensors) String() string {
+	if len(s.List) == 0 {
+		return "-"
+	}
+	names := make([]string, len(s.List))
+	for i, sensor := range s.List {
+		names[i] = string(sensor)
+		if sensor == SensorFingerprint {
+			var details []string
+			for _, detail := range []string{s.Fingerprint.Placement, s.Fingerprint.Kind} {
+				if detail != "" {
+					details = append(details, detail)
+				}
+			}
+			if len(details) > 0 {
+				names[i] += " (" + strings.Join(details, ", ") + ")"
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,53 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSensors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  *Sensors
+	}{
+		{"12.2", nil},
+		{"V1", nil},
+		{"", nil},
+		{"Accelerometer", &Sensors{List: []Sensor{SensorAccelerometer}}},
+		{
+			"Fingerprint (under display, optical), accelerometer",
+			&Sensors{List: []Sensor{SensorAccelerometer, SensorFingerprint}, Fingerprint: Fingerprint{Placement: "under display", Kind: "optical"}},
+		},
+		{
+			"Face ID, accelerometer, gyro, proximity, compass, barometer",
+			&Sensors{List: []Sensor{SensorAccelerometer, SensorBarometer, SensorCompass, SensorFaceRecognition, SensorGyro, SensorProximity}},
+		},
+		{
+			"compassFingerprint (rear-mounted)",
+			&Sensors{List: []Sensor{SensorCompass, SensorFingerprint}, Fingerprint: Fingerprint{Placement: "rear-mounted"}},
+		},
+		{"compass - India only, baroceptor", &Sensors{List: []Sensor{SensorBarometer, SensorCompass}}},
+		{"Dual fingerprint (side-mounted)", &Sensors{List: []Sensor{SensorFingerprint}, Fingerprint: Fingerprint{Placement: "side-mounted"}}},
+		{"SAR, SpO2", &Sensors{List: []Sensor{"sar", SensorSpO2}}},
+	}
+
+	for _, test := range tests {
+		got := ParseSensors(test.input)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseSensors(%q) = %+v, want %+v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestSensorsString(t *testing.T) {
+	sensors := ParseSensors("Fingerprint (under display, optical), accelerometer")
+	want := "accelerometer, fingerprint (under display, optical)"
+
+	if got := sensors.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !sensors.Has(SensorAccelerometer) || sensors.Has(SensorGyro) {
+		t.Errorf("Has() does not match %v", sensors.List)
+	}
+}
@@ -0,0 +1,158 @@
+package phonedb
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SimForm is the physical size of a SIM card.
+type SimForm int
+
+const (
+	// SimMini is the full size Mini-SIM.
+	SimMini SimForm = iota
+	// SimMicro is the Micro-SIM.
+	SimMicro
+	// SimNano is the Nano-SIM.
+	SimNano
+)
+
+// String implements the Stringer interface for SimForm.
+func (f SimForm) String() string {
+	switch f {
+	case SimMini:
+		return "Mini-SIM"
+	case SimMicro:
+		return "Micro-SIM"
+	default:
+		return "Nano-SIM"
+	}
+}
+
+// SimConfig describes the SIM card slots of a phone.
+type SimConfig struct {
+	// card sizes accepted, in the order listed and without duplicates
+	FormFactors []SimForm
+	// number of physical SIM slots, or the largest number if the phone is
+	// sold in several variants; 0 if unknown or the phone only has an eSIM
+	Slots int
+	// whether the second SIM slot is shared with the memory card
+	Hybrid bool
+	// whether the phone supports an embedded SIM
+	ESIM bool
+	// whether both SIMs are on stand-by at the same time
+	DualStandBy bool
+	// whether the phone is also sold as a single SIM variant
+	SingleVariant bool
+}
+
+var (
+	// "Nano-SIM", "Micro-SIM" or "MIni-SIM"
+	simFormRe = regexp.MustCompile(`(?i)\b(mini|micro|nano)-sim\b`)
+	// "eSIM" as a word of its own
+	esimRe = regexp.MustCompile(`(?i)\besim\b`)
+)
+
+// ParseSim decomposes a body_sim string such as
+// "Hybrid Dual SIM (Nano-SIM, dual stand-by)" into a SimConfig. The values
+// "no" and "yes" (case-insensitive) say nothing about the configuration, so
+// for them, or when nothing is recognised, it returns nil.
+func ParseSim(simStr string) *SimConfig {
+	lower := strings.ToLower(simStr)
+	if lower == "no" || lower == "yes" {
+		return nil
+	}
+
+	var sim SimConfig
+	found := false
+
+	seen := make(map[SimForm]bool)
+	for _, match := range simFormRe.FindAllStringSubmatch(simStr, -1) {
+		var form SimForm
+		switch strings.ToLower(match[1]) {
+		case "mini":
+			form = SimMini
+		case "micro":
+			form = SimMicro
+		default:
+			form = SimNano
+		}
+		if !seen[form] {
+			seen[form] = true
+			sim.FormFactors = append(sim.FormFactors, form)
+		}
+		found = true
+	}
+
+	if esimRe.MatchString(simStr) {
+		sim.ESIM = true
+		found = true
+	}
+
+	switch {
+	case strings.Contains(lower, "dual sim"):
+		sim.Slots = 2
+		sim.SingleVariant = strings.Contains(lower, "single sim")
+		found = true
+	case strings.Contains(lower, "single sim") || len(sim.FormFactors) > 0:
+		sim.Slots = 1
+		found = true
+	}
+
+	sim.Hybrid = strings.Contains(lower, "hybrid")
+	sim.DualStandBy = strings.Contains(lower, "dual stand-by")
+
+	if !found {
+		return nil
+	}
+	return &sim
+}
+
+// IsDual reports whether the phone can hold two SIM cards at once, counting
+// an eSIM alongside a physical slot.
+func (s SimConfig) IsDual() bool {
+	return s.Slots >= 2 || s.Slots == 1 && s.ESIM
+}
+
+// String implements the Stringer interface for SimConfig, using the same
+// layout as the dataset.
+func (s SimConfig) String() string {
+	var details []string
+	if len(s.FormFactors) > 0 {
+		forms := make([]string, len(s.FormFactors))
+		for i, form := range s.FormFactors {
+			forms[i] = form.String()
+		}
+		details = append(details, strings.Join(forms, "/"))
+	}
+	if s.ESIM {
+		details = append(details, "eSIM")
+	}
+	if s.DualStandBy {
+		details = append(details, "dual stand-by")
+	}
+
+	var kind string
+	switch {
+	case s.Slots >= 2 && s.Hybrid:
+		kind = "Hybrid Dual SIM"
+	case s.Slots >= 2:
+		kind = "Dual SIM"
+	case s.Slots == 1:
+		kind = "Single SIM"
+	}
+	if s.SingleVariant {
+		kind = "Single SIM or " + kind
+	}
+
+	switch {
+	case kind == "" && len(details) == 0:
+		return "-"
+	case kind == "":
+		return strings.Join(details, ", ")
+	case len(details) == 0:
+		return kind
+	default:
+		return kind + " (" + strings.Join(details, ", ") + ")"
+	}
+}
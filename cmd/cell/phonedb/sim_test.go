@@ -0,0 +1,55 @@
+package phonedb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSim(t *testing.T) {
+	tests := []struct {
+		input string
+		want  *SimConfig
+	}{
+		{"yes", nil},
+		{"no", nil},
+		{"", nil},
+		{"Mini-SIM", &SimConfig{FormFactors: []SimForm{SimMini}, Slots: 1}},
+		{"Dual SIM (Nano-SIM, dual stand-by)", &SimConfig{FormFactors: []SimForm{SimNano}, Slots: 2, DualStandBy: true}},
+		{"Hybrid Dual SIM (Nano-SIM, dual stand-by)", &SimConfig{FormFactors: []SimForm{SimNano}, Slots: 2, Hybrid: true, DualStandBy: true}},
+		{
+			"Single SIM (Nano-SIM/eSIM) or Hybrid Dual SIM (Nano-SIM, dual stand-by)",
+			&SimConfig{FormFactors: []SimForm{SimNano}, Slots: 2, Hybrid: true, ESIM: true, DualStandBy: true, SingleVariant: true},
+		},
+		{"Dual SIM (Micro-SIM/Mini-SIM, dual stand-by)", &SimConfig{FormFactors: []SimForm{SimMicro, SimMini}, Slots: 2, DualStandBy: true}},
+		{"Nano-SIM card & eSIM", &SimConfig{FormFactors: []SimForm{SimNano}, Slots: 1, ESIM: true}},
+		{"eSIM", &SimConfig{ESIM: true}},
+		{"Dual SIM", &SimConfig{Slots: 2}},
+	}
+
+	for _, test := range tests {
+		got := ParseSim(test.input)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseSim(%q) = %+v, want %+v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestSimConfigString(t *testing.T) {
+	inputs := []string{
+		"Mini-SIM",
+		"Hybrid Dual SIM (Nano-SIM, dual stand-by)",
+		"Single SIM or Dual SIM (Nano-SIM, dual stand-by)",
+	}
+	want := []string{
+		"Single SIM (Mini-SIM)",
+		"Hybrid Dual SIM (Nano-SIM, dual stand-by)",
+		"Single SIM or Dual SIM (Nano-SIM, dual stand-by)",
+	}
+
+	for i, input := range inputs {
+		if got := ParseSim(input).String(); got != want[i] {
+			t.Errorf("ParseSim(%q).String() = %q, want %q", input, got, want[i])
+		}
+	}
+}
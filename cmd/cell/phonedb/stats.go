@@ -0,0 +1,579 @@
+package phonedb
+
+import (
+	"sort"
+)
+
+// AverageWeight calculates the average weight of the phones in the catalog.
+// Phones with an unknown weight are excluded from the average.
+func (c *Catalog) AverageWeight() Result[float64] {
+	cells, excluded := c.partition(func(cell *Cell) bool { return cell.bodyWeight.Known() })
+
+	var totalWeight float64
+	for _, cell := range cells {
+		totalWeight += cell.bodyWeight.Value()
+	}
+
+	result := Result[float64]{Included: len(cells), Excluded: excluded}
+	if len(cells) > 0 {
+		result.Value = totalWeight / float64(len(cells))
+	}
+	return result
+}
+
+// AverageDisplaySize calculates the average display size of the phones in
+// the catalog. Phones with an unknown display size are excluded from the
+// average.
+func (c *Catalog) AverageDisplaySize() Result[float64] {
+	cells, excluded := c.partition(func(cell *Cell) bool { return cell.displaySize.Known() })
+
+	sizes := make([]float64, len(cells))
+	for i, cell := range cells {
+		sizes[i] = cell.displaySize.Value()
+	}
+	return Result[float64]{Value: mean(sizes), Included: len(cells), Excluded: excluded}
+}
+
+// AverageThickness calculates the average body thickness in millimetres of
+// the phones in the catalog. Phones without a known depth are excluded from
+// the average.
+func (c *Catalog) AverageThickness() Result[float64] {
+	cells, excluded := c.partition(hasThickness)
+
+	depths := make([]float64, len(cells))
+	for i, cell := range cells {
+		depths[i] = cell.bodyDimensions.Value().Depth
+	}
+	return Result[float64]{Value: mean(depths), Included: len(cells), Excluded: excluded}
+}
+
+// AverageThicknessByYear calculates the average body thickness in millimetres
+// of the phones announced in each year. Phones without a known depth or
+// announcement year are excluded.
+func (c *Catalog) AverageThicknessByYear() Result[map[uint]float64] {
+	return c.averageByYear(hasThickness, func(cell *Cell) float64 { return cell.bodyDimensions.Value().Depth })
+}
+
+// AverageVolumeByYear calculates the average body volume in cubic centimetres
+// of the phones announced in each year. Phones without all three dimensions
+// or an announcement year are excluded.
+func (c *Catalog) AverageVolumeByYear() Result[map[uint]float64] {
+	return c.averageByYear(hasVolume, func(cell *Cell) float64 { return cell.bodyDimensions.Value().Volume() })
+}
+
+// hasThickness reports whether the depth of the phone's body is known.
+func hasThickness(cell *Cell) bool {
+	dimensions, ok := cell.bodyDimensions.Get()
+	return ok && dimensions.Depth > 0
+}
+
+// hasVolume reports whether all three dimensions of the phone's body are
+// known.
+func hasVolume(cell *Cell) bool {
+	dimensions, ok := cell.bodyDimensions.Get()
+	return ok && dimensions.Volume() > 0
+}
+
+// hasYear reports whether the year the phone was announced is known.
+func hasYear(cell *Cell) bool {
+	return cell.launch.Announced.Known()
+}
+
+// averageByYear averages value over the phones announced in each year,
+// excluding phones without a known announcement year or for which known
+// reports false.
+func (c *Catalog) averageByYear(known func(*Cell) bool, value func(*Cell) float64) Result[map[uint]float64] {
+	return GroupBy(c, ByYear, Mean(func(cell *Cell) (float64, bool) {
+		if !known(cell) {
+			return 0, false
+		}
+		return value(cell), true
+	}))
+}
+
+// RankByPixelDensity returns the phones in the catalog with a known pixel
+// density, ordered from the densest display to the least dense. Phones with
+// the same density are ordered by OEM and then model.
+func (c *Catalog) RankByPixelDensity() Result[[]*Cell] {
+	ranked, excluded := c.partition(func(cell *Cell) bool { return cell.PixelDensity().Known() })
+
+	sort.Slice(ranked, func(i, j int) bool {
+		di, dj := ranked[i].PixelDensity().Value(), ranked[j].PixelDensity().Value()
+		if di != dj {
+			return di > dj
+		}
+		if ranked[i].oem != ranked[j].oem {
+			return ranked[i].oem < ranked[j].oem
+		}
+		return ranked[i].model < ranked[j].model
+	})
+
+	return Result[[]*Cell]{Value: ranked, Included: len(ranked), Excluded: excluded}
+}
+
+// CountResolutionsByYear counts, for each announcement year, how many phones
+// have each pixel resolution, keyed by Resolution.Label. Phones without known
+// pixel dimensions or announcement year are excluded.
+func (c *Catalog) CountResolutionsByYear() Result[map[uint]map[string]int] {
+	cells, excluded := c.partition(func(cell *Cell) bool {
+		resolution, ok := cell.displayResolution.Get()
+		return hasYear(cell) && ok && resolution.HasPixels()
+	})
+
+	counts := make(map[uint]map[string]int)
+	for _, cell := range cells {
+		year := cell.launch.Announced.Year
+		if counts[year] == nil {
+			counts[year] = make(map[string]int)
+		}
+		counts[year][cell.displayResolution.Value().Label()]++
+	}
+
+	return Result[map[uint]map[string]int]{Value: counts, Included: len(cells), Excluded: excluded}
+}
+
+// PanelShareByYear calculates, for each announcement year, the fraction of
+// phones using each panel technology. Phones with an unknown panel or
+// announcement year are excluded from both the counts and the totals, so the
+// shares for a year add up to 1.
+func (c *Catalog) PanelShareByYear() Result[map[uint]map[Panel]float64] {
+	cells, excluded := c.partition(func(cell *Cell) bool {
+		return hasYear(cell) && cell.displayType.Value().Panel != PanelUnknown
+	})
+
+	counts := make(map[uint]map[Panel]int)
+	for _, cell := range cells {
+		year := cell.launch.Announced.Year
+		if counts[year] == nil {
+			counts[year] = make(map[Panel]int)
+		}
+		counts[year][cell.displayType.Value().Panel]++
+	}
+
+	return Result[map[uint]map[Panel]float64]{Value: shares(counts), Included: len(cells), Excluded: excluded}
+}
+
+// OEMShareByYear calculates, for each announcement year, the fraction of the
+// phones announced that year made by each OEM. Phones without a known
+// announcement year are excluded.
+func (c *Catalog) OEMShareByYear() Result[map[uint]map[string]float64] {
+	byYearAndOEM := GroupBy(c, ByPair(ByYear, ByOEM), Count())
+
+	counts := make(map[uint]map[string]int)
+	for k, count := range byYearAndOEM.Value {
+		if counts[k.First] == nil {
+			counts[k.First] = make(map[string]int)
+		}
+		counts[k.First][k.Second] = count
+	}
+	return Result[map[uint]map[string]float64]{Value: shares(counts), Included: byYearAndOEM.Included, Excluded: byYearAndOEM.Excluded}
+}
+
+// shares turns the counts of each year into fractions of that year's total.
+func shares[K comparable](counts map[uint]map[K]int) map[uint]map[K]float64 {
+	fractions := make(map[uint]map[K]float64)
+	for year, yearCounts := range counts {
+		total := 0
+		for _, count := range yearCounts {
+			total += count
+		}
+		fractions[year] = make(map[K]float64)
+		for k, count := range yearCounts {
+			fractions[year][k] = float64(count) / float64(total)
+		}
+	}
+	return fractions
+}
+
+// SimCounts is the number of single and dual SIM phones.
+type SimCounts struct {
+	Single int
+	Dual   int
+}
+
+// CountSimSlotsByYear counts the single and dual SIM phones announced in each
+// year. A phone sold in both variants, or with an eSIM beside a physical
+// slot, counts as dual SIM. Phones with an unknown SIM configuration or
+// announcement year are excluded.
+func (c *Catalog) CountSimSlotsByYear() Result[map[uint]SimCounts] {
+	cells, excluded := c.partition(func(cell *Cell) bool {
+		sim, ok := cell.bodySim.Get()
+		return hasYear(cell) && ok && (sim.Slots > 0 || sim.ESIM)
+	})
+
+	counts := make(map[uint]SimCounts)
+	for _, cell := range cells {
+		year := cell.launch.Announced.Year
+		yearCounts := counts[year]
+		if cell.bodySim.Value().IsDual() {
+			yearCounts.Dual++
+		} else {
+			yearCounts.Single++
+		}
+		counts[year] = yearCounts
+	}
+
+	return Result[map[uint]SimCounts]{Value: counts, Included: len(cells), Excluded: excluded}
+}
+
+// FindDualSimOvertakeYear returns the earliest announcement year in which
+// dual SIM phones outnumbered single SIM phones, as counted by
+// CountSimSlotsByYear. It reports false if that never happened.
+func (c *Catalog) FindDualSimOvertakeYear() (uint, bool) {
+	counts := c.CountSimSlotsByYear().Value
+
+	years := make([]uint, 0, len(counts))
+	for year := range counts {
+		years = append(years, year)
+	}
+	sort.Slice(years, func(i, j int) bool { return years[i] < years[j] })
+
+	for _, year := range years {
+		if counts[year].Dual > counts[year].Single {
+			return year, true
+		}
+	}
+	return 0, false
+}
+
+// LagStats summarizes announce-to-release lags in days.
+type LagStats struct {
+	// number of phones with a known lag
+	Count int `json:"count"`
+	// average lag in days
+	Mean float64 `json:"mean"`
+	// median lag in days
+	Median float64 `json:"median"`
+}
+
+// MeanMonths returns the average lag in months.
+func (s LagStats) MeanMonths() float64 {
+	return s.Mean / daysPerMonth
+}
+
+// MedianMonths returns the median lag in months.
+func (s LagStats) MedianMonths() float64 {
+	return s.Median / daysPerMonth
+}
+
+// LagByOEM summarizes the announce-to-release lag of each OEM's phones.
+// Phones without both an announcement and a release date are excluded.
+func (c *Catalog) LagByOEM() Result[map[string]LagStats] {
+	return groupLags(c, func(cell *Cell) (string, bool) { return cell.oem, true })
+}
+
+// LagByYear summarizes the announce-to-release lag of the phones announced
+// in each year. Phones without both an announcement and a release date are
+// excluded.
+func (c *Catalog) LagByYear() Result[map[uint]LagStats] {
+	return groupLags(c, func(cell *Cell) (uint, bool) {
+		return cell.launch.Announced.Year, hasYear(cell)
+	})
+}
+
+// groupLags collects the lag of every phone in the catalog under the group
+// key returns for it, excluding phones without a lag or for which key
+// reports false, and summarizes each group.
+func groupLags[K comparable](c *Catalog, key func(*Cell) (K, bool)) Result[map[K]LagStats] {
+	return GroupBy(c, key, Aggregate[LagStats]{
+		Known: func(cell *Cell) bool {
+			_, ok := cell.launch.Lag()
+			return ok
+		},
+		Reduce: func(cells []*Cell) LagStats {
+			days := make([]float64, len(cells))
+			for i, cell := range cells {
+				lag, _ := cell.launch.Lag()
+				days[i] = lag.Days
+			}
+			return LagStats{Count: len(days), Mean: mean(days), Median: median(days)}
+		},
+	})
+}
+
+// mean returns the average of values, or 0 if there are none.
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+// median returns the middle value of values, or the average of the two
+// middle values if there is an even number of them. It returns 0 if there
+// are no values. values is not modified.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// YearCounts represents the number of phones launched in each year.
+type YearCounts struct {
+	Counts map[uint]int
+	Years  []uint
+}
+
+// CountPhonesByYear counts the number of phones launched in each year
+// and returns a YearCounts object which includes a sorted list of years
+// for which data exists. Phones without a known announcement year are
+// excluded.
+func (c *Catalog) CountPhonesByYear() Result[YearCounts] {
+	counts := GroupBy(c, ByYear, Count())
+	return Result[YearCounts]{
+		Value: YearCounts{
+			Counts: counts.Value,
+			Years:  SortedKeys(counts.Value),
+		},
+		Included: counts.Included,
+		Excluded: counts.Excluded,
+	}
+}
+
+// hasOS reports whether the operating system of the phone is known.
+func hasOS(cell *Cell) bool {
+	return cell.platformOS.Known()
+}
+
+// CountUniqueOS counts the number of unique operating system releases, such
+// as "Android 9.0", used in the catalog. Phones without a known operating
+// system are excluded.
+func (c *Catalog) CountUniqueOS() Result[int] {
+	cells, excluded := c.partition(hasOS)
+
+	osSet := make(map[string]struct{})
+	for _, cell := range cells {
+		osSet[cell.platformOS.Value().Name()] = struct{}{}
+	}
+
+	return Result[int]{Value: len(osSet), Included: len(cells), Excluded: excluded}
+}
+
+// CountUniqueOSFamilies counts the number of unique operating system
+// families, such as "Android", used in the catalog. Phones without a known
+// operating system are excluded.
+func (c *Catalog) CountUniqueOSFamilies() Result[int] {
+	cells, excluded := c.partition(hasOS)
+
+	familySet := make(map[string]struct{})
+	for _, cell := range cells {
+		familySet[cell.platformOS.Value().Family] = struct{}{}
+	}
+
+	return Result[int]{Value: len(familySet), Included: len(cells), Excluded: excluded}
+}
+
+// OSVersionShareByYear calculates, for each announcement year, the fraction
+// of phones running family that ship with each major version, such as the
+// share of 2019 Android phones on Android 9. Phones of family without a known
+// version or announcement year are excluded, as are phones of other
+// families.
+func (c *Catalog) OSVersionShareByYear(family string) Result[map[uint]map[int]float64] {
+	cells, excluded := c.partition(func(cell *Cell) bool {
+		os, ok := cell.platformOS.Get()
+		return hasYear(cell) && ok && os.Family == family && os.HasVersion
+	})
+
+	counts := make(map[uint]map[int]int)
+	for _, cell := range cells {
+		year := cell.launch.Announced.Year
+		if counts[year] == nil {
+			counts[year] = make(map[int]int)
+		}
+		counts[year][cell.platformOS.Value().Version.Major]++
+	}
+
+	return Result[map[uint]map[int]float64]{Value: shares(counts), Included: len(cells), Excluded: excluded}
+}
+
+// CountPhonesByOEM counts the number of phones produced by each OEM
+// in the catalog.
+func (c *Catalog) CountPhonesByOEM() map[string]int {
+	return GroupBy(c, ByOEM, Count()).Value
+}
+
+// FindLatestPhoneByOEM finds the most recently launched phone by each OEM
+// in the catalog, going by announcement year. Of several phones announced
+// in the same year, the one whose model sorts first is returned.
+func (c *Catalog) FindLatestPhoneByOEM() map[string]*Cell {
+	// an unknown year is 0, so every OEM has a latest phone even if none of
+	// its years is known
+	return GroupBy(c, ByOEM, ArgMax(func(cell *Cell) (float64, bool) {
+		return float64(cell.launch.Announced.Year), true
+	})).Value
+}
+
+// HeaviestAndLightest holds the heaviest and lightest phones of a catalog.
+type HeaviestAndLightest struct {
+	Heaviest *Cell
+	Lightest *Cell
+}
+
+// FindHeaviestAndLightestPhones finds the heaviest and lightest phones
+// in the catalog. Phones with an unknown weight are excluded. Both phones
+// are nil if no weight is known. Of several phones with the same weight, the
+// first in Phones order is returned.
+func (c *Catalog) FindHeaviestAndLightestPhones() Result[HeaviestAndLightest] {
+	cells, excluded := c.partition(func(cell *Cell) bool { return cell.bodyWeight.Known() })
+
+	var found HeaviestAndLightest
+	for _, cell := range cells {
+		weight := cell.bodyWeight.Value()
+		if found.Heaviest == nil || weight > found.Heaviest.bodyWeight.Value() {
+			found.Heaviest = cell
+		}
+		if found.Lightest == nil || weight < found.Lightest.bodyWeight.Value() {
+			found.Lightest = cell
+		}
+	}
+	return Result[HeaviestAndLightest]{Value: found, Included: len(cells), Excluded: excluded}
+}
+
+// AverageWeightByOEM calculates the average weight of the phones
+// for each OEM in the catalog. Phones with an unknown weight are excluded.
+// It returns a map of OEMs and their average phone weights.
+func (c *Catalog) AverageWeightByOEM() Result[map[string]float64] {
+	return GroupBy(c, ByOEM, Mean(weight))
+}
+
+// weight is the Metric of a phone's body weight in grams.
+func weight(cell *Cell) (float64, bool) {
+	return cell.bodyWeight.Get()
+}
+
+// FindOEMWithHighestAverageWeight returns the OEM whose phones have the highest
+// average body weight in the catalog. Of several OEMs with the same average,
+// the one whose name sorts first is returned.
+func (c *Catalog) FindOEMWithHighestAverageWeight() string {
+	averages := c.AverageWeightByOEM().Value
+	oems := make([]string, 0, len(averages))
+	for oem := range averages {
+		oems = append(oems, oem)
+	}
+	sort.Strings(oems)
+
+	var maxOEM string
+	var maxAvg float64
+	for _, oem := range oems {
+		if avg := averages[oem]; avg > maxAvg {
+			maxOEM = oem
+			maxAvg = avg
+		}
+	}
+	return maxOEM
+}
+
+// PhoneDetails struct to hold the oem and model of a phone
+type PhoneDetails struct {
+	OEM   string `json:"oem"`
+	Model string `json:"model"`
+}
+
+// FindPhonesAnnouncedAndReleasedDifferentYears checks if there are any phones that were announced
+// in one year and released in another. If such phones exist, it returns their OEM and model,
+// in Phones order.
+// Phones without both an announcement and a release date are excluded.
+func (c *Catalog) FindPhonesAnnouncedAndReleasedDifferentYears() Result[[]PhoneDetails] {
+	cells, excluded := c.partition(func(cell *Cell) bool {
+		return cell.launch.Announced.Known() && cell.launch.Released.Known()
+	})
+
+	var phoneDetails []PhoneDetails
+	for _, cell := range cells {
+		if cell.launch.Announced.Year != cell.launch.Released.Year {
+			phoneDetails = append(phoneDetails, PhoneDetails{cell.oem, cell.model})
+		}
+	}
+	return Result[[]PhoneDetails]{Value: phoneDetails, Included: len(cells), Excluded: excluded}
+}
+
+// hasSensors reports whether the sensors of the phone are known.
+func hasSensors(cell *Cell) bool {
+	return cell.featuresSensors.Known()
+}
+
+// CountPhonesWithOneSensor counts the number of phones with only one feature sensor.
+// Phones with unknown sensors are excluded.
+func (c *Catalog) CountPhonesWithOneSensor() Result[int] {
+	cells, excluded := c.partition(hasSensors)
+
+	count := 0
+	for _, cell := range cells {
+		if cell.featuresSensors.Value().Len() == 1 {
+			count++
+		}
+	}
+	return Result[int]{Value: count, Included: len(cells), Excluded: excluded}
+}
+
+// CountPhonesBySensor counts the number of phones that have each sensor.
+// Phones with unknown sensors are excluded.
+func (c *Catalog) CountPhonesBySensor() Result[map[Sensor]int] {
+	cells, excluded := c.partition(hasSensors)
+
+	counts := make(map[Sensor]int)
+	for _, cell := range cells {
+		for _, sensor := range cell.featuresSensors.Value().List {
+			counts[sensor]++
+		}
+	}
+	return Result[map[Sensor]int]{Value: counts, Included: len(cells), Excluded: excluded}
+}
+
+// SensorAdoptionByYear calculates, for each announcement year, the fraction
+// of phones that have each sensor. Phones with unknown sensors or
+// announcement year are excluded from the totals.
+func (c *Catalog) SensorAdoptionByYear() Result[map[uint]map[Sensor]float64] {
+	cells, excluded := c.partition(func(cell *Cell) bool { return hasYear(cell) && hasSensors(cell) })
+
+	counts := make(map[uint]map[Sensor]int)
+	totals := make(map[uint]int)
+	for _, cell := range cells {
+		year := cell.launch.Announced.Year
+		if counts[year] == nil {
+			counts[year] = make(map[Sensor]int)
+		}
+		for _, sensor := range cell.featuresSensors.Value().List {
+			counts[year][sensor]++
+		}
+		totals[year]++
+	}
+
+	adoption := make(map[uint]map[Sensor]float64)
+	for year, sensors := range counts {
+		adoption[year] = make(map[Sensor]float64)
+		for sensor, count := range sensors {
+			adoption[year][sensor] = float64(count) / float64(totals[year])
+		}
+	}
+
+	return Result[map[uint]map[Sensor]float64]{Value: adoption, Included: len(cells), Excluded: excluded}
+}
+
+// FindMostLaunchesIn2000s returns the year in the 2000s that had the most phone launches.
+// Of several years with the same count, the earliest is returned, and 0 if no phone
+// was launched in the 2000s.
+func FindMostLaunchesIn2000s(yearCounts YearCounts) uint {
+	var maxYear uint
+	var maxCount int
+
+	for year := uint(2000); year < 2010; year++ {
+		if count := yearCounts.Counts[year]; count > maxCount {
+			maxYear = year
+			maxCount = count
+		}
+	}
+
+	return maxYear
+}
@@ -0,0 +1,470 @@
+package phonedb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAverageWeight(t *testing.T) {
+	// Creating a test cell map
+	cells := &Catalog{cells: map[string]*Cell{
+		"Google-Pixel 4 XL":  {bodyWeight: Some[float64](193.0)},
+		"Google-Pixel 3":     {bodyWeight: Some[float64](148.0)},
+		"Samsung-Galaxy S10": {bodyWeight: Some[float64](157.0)},
+		"Empty-Weight":       {}, // This one should be excluded from the average.
+	}}
+
+	// Execute the function with the test cell map
+	result := cells.AverageWeight()
+
+	// Expected average weight = (193.0 + 148.0 + 157.0) / 3 = 166.0
+	expected := Result[float64]{Value: 166.0, Included: 3, Excluded: 1}
+
+	// If the result does not match the expected average, fail the test
+	if result != expected {
+		t.Errorf("cells.AverageWeight() = %+v; want %+v", result, expected)
+	}
+}
+
+func TestAverageDisplaySize(t *testing.T) {
+	// Creating a test cell map
+	cells := &Catalog{cells: map[string]*Cell{
+		"Google-Pixel 4 XL":  {displaySize: Some(6.3)},
+		"Google-Pixel 3":     {displaySize: Some(5.5)},
+		"Samsung-Galaxy S10": {displaySize: Some(6.1)},
+		"Zero-Display-Size":  {}, // This one should be excluded from the average.
+	}}
+
+	// Execute the function with the test cell map
+	got := cells.AverageDisplaySize()
+
+	got.Value = round(got.Value, 2)
+
+	want := Result[float64]{Value: 5.97, Included: 3, Excluded: 1}
+
+	if got != want {
+		t.Errorf("cells.AverageDisplaySize() = %+v; want %+v", got, want)
+	}
+}
+
+func round(val float64, precision int) float64 {
+	p := math.Pow(10, float64(precision))
+	return math.Round(val*p) / p
+}
+
+func TestCountPhonesByYear(t *testing.T) {
+	// Create a map of cells
+	cells := &Catalog{cells: map[string]*Cell{
+		"Google-Pixel 4 XL":  {launch: announcedIn(2019)},
+		"Google-Pixel 3":     {launch: announcedIn(2018)},
+		"Samsung-Galaxy S10": {launch: announcedIn(2019)},
+		"Samsung-Galaxy S9":  {launch: announcedIn(2018)},
+		"No-Announced-Year":  {launch: announcedIn(0)}, // This one should be ignored.
+	}}
+
+	// Expected result
+	expected := Result[YearCounts]{
+		Value: YearCounts{
+			Counts: map[uint]int{
+				2018: 2,
+				2019: 2,
+			},
+			Years: []uint{2018, 2019},
+		},
+		Included: 4,
+		Excluded: 1,
+	}
+
+	// Execute the function with the test data
+	result := cells.CountPhonesByYear()
+
+	// If the result does not match the expected counts and years, fail the test
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("cells.CountPhonesByYear() = %v; want %v", result, expected)
+	}
+}
+
+func TestCountUniqueOS(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {platformOS: Some(OSInfo{Family: "Android"})},
+		"Phone2": {platformOS: Some(OSInfo{Family: "Android"})},
+		"Phone3": {platformOS: Some(OSInfo{Family: "iOS"})},
+		"Phone4": {platformOS: Some(OSInfo{Family: "Windows"})},
+		"Phone5": {platformOS: Some(OSInfo{Family: "iOS"})},
+		"Phone6": {},
+		"Phone7": {platformOS: Some(OSInfo{Family: "Android"})},
+	}}
+
+	// We expect 3 unique operating systems: Android, iOS, and Windows
+	want := Result[int]{Value: 3, Included: 6, Excluded: 1}
+	got := cells.CountUniqueOS()
+
+	if got != want {
+		t.Errorf("cells.CountUniqueOS() = %+v; want %+v", got, want)
+	}
+}
+
+func TestCountUniqueOSFamiliesAndVersions(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {platformOS: FromPtr(ParsePlatformOS("Android 9.0 (Pie), EMUI 9.1"))},
+		"Phone2": {platformOS: FromPtr(ParsePlatformOS("Android 9.0 (Pie)"))},
+		"Phone3": {platformOS: FromPtr(ParsePlatformOS("Android 10, MIUI 11"))},
+		"Phone4": {platformOS: FromPtr(ParsePlatformOS("Symbian 9.4, Series 60 rel. 5"))},
+	}}
+
+	if got := cells.CountUniqueOS().Value; got != 3 {
+		t.Errorf("cells.CountUniqueOS() = %d; want 3", got)
+	}
+	if got := cells.CountUniqueOSFamilies().Value; got != 2 {
+		t.Errorf("cells.CountUniqueOSFamilies() = %d; want 2", got)
+	}
+}
+
+func TestCountPhonesByOEM(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {oem: "Apple"},
+		"Phone2": {oem: "Apple"},
+		"Phone3": {oem: "Samsung"},
+		"Phone4": {oem: "Samsung"},
+		"Phone5": {oem: "Samsung"},
+		"Phone6": {oem: "Google"},
+	}}
+
+	want := map[string]int{"Apple": 2, "Samsung": 3, "Google": 1}
+	got := cells.CountPhonesByOEM()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cells.CountPhonesByOEM() = %v; want %v", got, want)
+	}
+}
+
+func TestFindLatestPhoneByOEM(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {oem: "Apple", model: "iPhone 13", launch: announcedIn(2023)},
+		"Phone2": {oem: "Apple", model: "iPhone 12", launch: announcedIn(2022)},
+		"Phone3": {oem: "Samsung", model: "Galaxy S21", launch: announcedIn(2022)},
+		"Phone4": {oem: "Samsung", model: "Galaxy S22", launch: announcedIn(2023)},
+		"Phone5": {oem: "Google", model: "Pixel 6", launch: announcedIn(2023)},
+	}}
+
+	want := map[string]*Cell{
+		"Apple":   {oem: "Apple", model: "iPhone 13", launch: announcedIn(2023)},
+		"Samsung": {oem: "Samsung", model: "Galaxy S22", launch: announcedIn(2023)},
+		"Google":  {oem: "Google", model: "Pixel 6", launch: announcedIn(2023)},
+	}
+
+	got := cells.FindLatestPhoneByOEM()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cells.FindLatestPhoneByOEM() = %v; want %v", got, want)
+	}
+}
+
+func TestFindHeaviestAndLightestPhones(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {model: "iPhone 13", bodyWeight: Some[float64](174)},
+		"Phone2": {model: "iPhone 12 Mini", bodyWeight: Some[float64](135)},
+		"Phone3": {model: "Galaxy S21"}, // This should be ignored
+		"Phone4": {model: "Galaxy S22", bodyWeight: Some[float64](200)},
+		"Phone5": {model: "Pixel 6", bodyWeight: Some[float64](143)},
+	}}
+
+	wantHeaviest := &Cell{model: "Galaxy S22", bodyWeight: Some[float64](200)}
+	wantLightest := &Cell{model: "iPhone 12 Mini", bodyWeight: Some[float64](135)}
+
+	result := cells.FindHeaviestAndLightestPhones()
+	gotHeaviest, gotLightest := result.Value.Heaviest, result.Value.Lightest
+
+	if !reflect.DeepEqual(gotHeaviest, wantHeaviest) {
+		t.Errorf("Heaviest phone incorrect, got: %v, want: %v.", gotHeaviest, wantHeaviest)
+	}
+
+	if !reflect.DeepEqual(gotLightest, wantLightest) {
+		t.Errorf("Lightest phone incorrect, got: %v, want: %v.", gotLightest, wantLightest)
+	}
+
+	if result.Included != 4 || result.Excluded != 1 {
+		t.Errorf("cells.FindHeaviestAndLightestPhones() counted %d included and %d excluded; want 4 and 1", result.Included, result.Excluded)
+	}
+}
+
+func TestAverageThickness(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {bodyDimensions: Some(Dimensions{Depth: 8})},
+		"Phone2": {bodyDimensions: Some(Dimensions{Depth: 10})},
+		"Phone3": {}, // This one should be excluded from the average.
+	}}
+
+	want := Result[float64]{Value: 9, Included: 2, Excluded: 1}
+	if got := cells.AverageThickness(); got != want {
+		t.Errorf("cells.AverageThickness() = %+v; want %+v", got, want)
+	}
+}
+
+func TestAverageVolumeByYear(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {launch: announcedIn(2019), bodyDimensions: Some(Dimensions{100, 50, 10})},
+		"Phone2": {launch: announcedIn(2019), bodyDimensions: Some(Dimensions{100, 50, 20})},
+		"Phone3": {launch: announcedIn(2019), bodyDimensions: Some(Dimensions{Depth: 9})}, // No volume, should be ignored.
+		"Phone4": {launch: announcedIn(2020), bodyDimensions: Some(Dimensions{100, 60, 10})},
+		"Phone5": {launch: announcedIn(0), bodyDimensions: Some(Dimensions{100, 60, 10})}, // No year, should be ignored.
+	}}
+
+	want := Result[map[uint]float64]{Value: map[uint]float64{2019: 75, 2020: 60}, Included: 3, Excluded: 2}
+	got := cells.AverageVolumeByYear()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cells.AverageVolumeByYear() = %v; want %v", got, want)
+	}
+}
+
+func TestRankByPixelDensity(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {oem: "Apple", model: "iPhone 11", displayResolution: Some(Resolution{PPI: 326})},
+		"Phone2": {oem: "Sony", model: "Xperia 1", displayResolution: Some(Resolution{PPI: 643})},
+		"Phone3": {oem: "Google", model: "Pixel 4", displayResolution: Some(Resolution{PPI: 444})},
+		"Phone4": {oem: "Apple", model: "iPhone XR", displayResolution: Some(Resolution{PPI: 326})},
+		"Phone5": {oem: "Nokia", model: "3310", displayResolution: Some(Resolution{Lines: 5})}, // This should be ignored
+	}}
+
+	var got []string
+	for _, cell := range cells.RankByPixelDensity().Value {
+		got = append(got, cell.model)
+	}
+	want := []string{"Xperia 1", "Pixel 4", "iPhone 11", "iPhone XR"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cells.RankByPixelDensity() = %v; want %v", got, want)
+	}
+}
+
+func TestCountResolutionsByYear(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {launch: announcedIn(2019), displayResolution: Some(Resolution{Width: 1080, Height: 2340})},
+		"Phone2": {launch: announcedIn(2019), displayResolution: Some(Resolution{Width: 1080, Height: 2340})},
+		"Phone3": {launch: announcedIn(2019), displayResolution: Some(Resolution{Width: 720, Height: 1520})},
+		"Phone4": {launch: announcedIn(2005), displayResolution: Some(Resolution{Width: 128, Height: 160})},
+		"Phone5": {launch: announcedIn(2005), displayResolution: Some(Resolution{Lines: 6})}, // This should be ignored
+	}}
+
+	want := map[uint]map[string]int{
+		2019: {"1080 x 2340": 2, "720 x 1520": 1},
+		2005: {"128 x 160": 1},
+	}
+	got := cells.CountResolutionsByYear().Value
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cells.CountResolutionsByYear() = %v; want %v", got, want)
+	}
+}
+
+func TestFindPhonesAnnouncedAndReleasedDifferentYears(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {oem: "Apple", model: "iPhone 11", launch: LaunchInfo{
+			Announced: Date{Year: 2019, Precision: PrecisionYear},
+			Released:  Date{Year: 2019, Precision: PrecisionYear},
+		}},
+		"Phone2": {oem: "Nokia", model: "N97", launch: LaunchInfo{
+			Announced: Date{Year: 2008, Precision: PrecisionYear},
+			Released:  Date{Year: 2009, Precision: PrecisionYear},
+		}},
+		"Phone3": {oem: "Gigabyte", model: "GSmart", launch: LaunchInfo{ // Unknown announcement, should be ignored.
+			Released: Date{Year: 2010, Precision: PrecisionYear},
+		}},
+	}}
+
+	want := []PhoneDetails{{OEM: "Nokia", Model: "N97"}}
+	got := cells.FindPhonesAnnouncedAndReleasedDifferentYears().Value
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cells.FindPhonesAnnouncedAndReleasedDifferentYears() = %v; want %v", got, want)
+	}
+}
+
+// announcedIn returns a LaunchInfo announced in the given year, or an
+// unknown announcement if year is 0.
+func announcedIn(year uint) LaunchInfo {
+	if year == 0 {
+		return LaunchInfo{}
+	}
+	return LaunchInfo{Announced: Date{Year: year, Precision: PrecisionYear}}
+}
+
+func TestLagByOEM(t *testing.T) {
+	month := func(year uint, m time.Month) Date {
+		return Date{Year: year, Quarter: int(m-1)/3 + 1, Month: m, Precision: PrecisionMonth}
+	}
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {oem: "Apple", launch: LaunchInfo{Announced: month(2019, time.September), Released: month(2019, time.September)}},
+		"Phone2": {oem: "Apple", launch: LaunchInfo{Announced: month(2018, time.September), Released: month(2018, time.October)}},
+		"Phone3": {oem: "Apple", launch: LaunchInfo{Announced: month(2017, time.September), Released: month(2017, time.November)}},
+		"Phone4": {oem: "Nokia", launch: LaunchInfo{Announced: month(2008, time.December), Released: month(2009, time.June)}},
+		"Phone5": {oem: "Nokia", launch: LaunchInfo{Announced: month(2010, time.May)}}, // No release, should be ignored.
+	}}
+
+	result := cells.LagByOEM()
+	if result.Included != 4 || result.Excluded != 1 {
+		t.Errorf("cells.LagByOEM() counted %d included and %d excluded; want 4 and 1", result.Included, result.Excluded)
+	}
+
+	got := result.Value
+	apple := got["Apple"]
+	if apple.Count != 3 || !almostEqual(apple.MeanMonths(), 1, 1e-9) || !almostEqual(apple.MedianMonths(), 1, 1e-9) {
+		t.Errorf("cells.LagByOEM()[Apple] = %+v; want 3 phones with mean and median of 1 month", apple)
+	}
+	nokia := got["Nokia"]
+	if nokia.Count != 1 || !almostEqual(nokia.MeanMonths(), 6, 1e-9) {
+		t.Errorf("cells.LagByOEM()[Nokia] = %+v; want 1 phone with mean of 6 months", nokia)
+	}
+
+	byYear := cells.LagByYear().Value
+	if len(byYear) != 4 || byYear[2008].Count != 1 {
+		t.Errorf("cells.LagByYear() = %+v; want 4 years with one phone each", byYear)
+	}
+}
+
+func TestPanelShareByYear(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {launch: announcedIn(2019), displayType: Some(DisplayType{Panel: PanelAMOLED})},
+		"Phone2": {launch: announcedIn(2019), displayType: Some(DisplayType{Panel: PanelIPSLCD})},
+		"Phone3": {launch: announcedIn(2019), displayType: Some(DisplayType{Panel: PanelIPSLCD})},
+		"Phone4": {launch: announcedIn(2019), displayType: Some(DisplayType{Panel: PanelIPSLCD})},
+		"Phone5": {launch: announcedIn(2019), displayType: Some(DisplayType{})}, // Unknown panel, should be ignored.
+		"Phone6": {launch: announcedIn(2003), displayType: Some(DisplayType{Panel: PanelSTN})},
+	}}
+
+	want := map[uint]map[Panel]float64{
+		2019: {PanelAMOLED: 0.25, PanelIPSLCD: 0.75},
+		2003: {PanelSTN: 1},
+	}
+	got := cells.PanelShareByYear().Value
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cells.PanelShareByYear() = %v; want %v", got, want)
+	}
+}
+
+func TestOEMShareByYear(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {oem: "Samsung", launch: announcedIn(2019)},
+		"Phone2": {oem: "Samsung", launch: announcedIn(2019)},
+		"Phone3": {oem: "Samsung", launch: announcedIn(2019)},
+		"Phone4": {oem: "Google", launch: announcedIn(2019)},
+		"Phone5": {oem: "Nokia", launch: announcedIn(2000)},
+		"Phone6": {oem: "Nokia"}, // Unknown year, should be ignored.
+	}}
+
+	want := Result[map[uint]map[string]float64]{
+		Value:    map[uint]map[string]float64{2019: {"Samsung": 0.75, "Google": 0.25}, 2000: {"Nokia": 1}},
+		Included: 5,
+		Excluded: 1,
+	}
+	if got := cells.OEMShareByYear(); !reflect.DeepEqual(got, want) {
+		t.Errorf("cells.OEMShareByYear() = %v; want %v", got, want)
+	}
+}
+
+func TestFindDualSimOvertakeYear(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {launch: announcedIn(2010), bodySim: Some(SimConfig{Slots: 1})},
+		"Phone2": {launch: announcedIn(2010), bodySim: Some(SimConfig{Slots: 2})},
+		"Phone3": {launch: announcedIn(2011), bodySim: Some(SimConfig{Slots: 2})},
+		"Phone4": {launch: announcedIn(2011), bodySim: Some(SimConfig{Slots: 1, ESIM: true})},
+		"Phone5": {launch: announcedIn(2011), bodySim: Some(SimConfig{Slots: 1})},
+		"Phone6": {launch: announcedIn(2011)}, // Unknown SIM, should be ignored.
+		"Phone7": {launch: announcedIn(2012), bodySim: Some(SimConfig{Slots: 1})},
+	}}
+
+	wantCounts := map[uint]SimCounts{
+		2010: {Single: 1, Dual: 1},
+		2011: {Single: 1, Dual: 2},
+		2012: {Single: 1},
+	}
+	if got := cells.CountSimSlotsByYear().Value; !reflect.DeepEqual(got, wantCounts) {
+		t.Errorf("cells.CountSimSlotsByYear() = %v; want %v", got, wantCounts)
+	}
+
+	year, ok := cells.FindDualSimOvertakeYear()
+	if !ok || year != 2011 {
+		t.Errorf("cells.FindDualSimOvertakeYear() = %d, %v; want 2011, true", year, ok)
+	}
+}
+
+func TestCountPhonesWithOneSensor(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {featuresSensors: FromPtr(ParseSensors("Accelerometer"))},
+		"Phone2": {featuresSensors: FromPtr(ParseSensors("Fingerprint (under display, optical)"))},
+		"Phone3": {featuresSensors: FromPtr(ParseSensors("Fingerprint (rear-mounted), accelerometer"))},
+		"Phone4": {}, // No sensors, should not be counted.
+	}}
+
+	want := Result[int]{Value: 2, Included: 3, Excluded: 1}
+	if got := cells.CountPhonesWithOneSensor(); got != want {
+		t.Errorf("cells.CountPhonesWithOneSensor() = %+v; want %+v", got, want)
+	}
+}
+
+func TestSensorAdoptionByYear(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {launch: announcedIn(2019), featuresSensors: Some(Sensors{List: []Sensor{SensorAccelerometer, SensorGyro}})},
+		"Phone2": {launch: announcedIn(2019), featuresSensors: Some(Sensors{List: []Sensor{SensorAccelerometer}})},
+		"Phone3": {launch: announcedIn(2019)}, // No sensors, should be ignored.
+	}}
+
+	want := map[uint]map[Sensor]float64{2019: {SensorAccelerometer: 1, SensorGyro: 0.5}}
+	if got := cells.SensorAdoptionByYear().Value; !reflect.DeepEqual(got, want) {
+		t.Errorf("cells.SensorAdoptionByYear() = %v; want %v", got, want)
+	}
+
+	wantCounts := map[Sensor]int{SensorAccelerometer: 2, SensorGyro: 1}
+	if got := cells.CountPhonesBySensor().Value; !reflect.DeepEqual(got, wantCounts) {
+		t.Errorf("cells.CountPhonesBySensor() = %v; want %v", got, wantCounts)
+	}
+}
+
+func TestOSVersionShareByYear(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Phone1": {launch: announcedIn(2019), platformOS: Some(OSInfo{Family: "Android", Version: Version{Major: 9}, HasVersion: true})},
+		"Phone2": {launch: announcedIn(2019), platformOS: Some(OSInfo{Family: "Android", Version: Version{Major: 9}, HasVersion: true})},
+		"Phone3": {launch: announcedIn(2019), platformOS: Some(OSInfo{Family: "Android", Version: Version{Major: 10}, HasVersion: true})},
+		"Phone4": {launch: announcedIn(2019), platformOS: Some(OSInfo{Family: "Android", Version: Version{Major: 10}, HasVersion: true})},
+		"Phone5": {launch: announcedIn(2019), platformOS: Some(OSInfo{Family: "Android"})},                                                     // No version, should be ignored.
+		"Phone6": {launch: announcedIn(2019), platformOS: Some(OSInfo{Family: "Windows Phone", Version: Version{Major: 7}, HasVersion: true})}, // Other family, should be ignored.
+	}}
+
+	want := map[uint]map[int]float64{2019: {9: 0.5, 10: 0.5}}
+	if got := cells.OSVersionShareByYear("Android").Value; !reflect.DeepEqual(got, want) {
+		t.Errorf("cells.OSVersionShareByYear(Android) = %v; want %v", got, want)
+	}
+}
+
+func TestTiesAreBrokenByName(t *testing.T) {
+	cells := &Catalog{cells: map[string]*Cell{
+		"Sony-Xperia":   {oem: "Sony", model: "Xperia", bodyWeight: Some[float64](150), launch: announcedIn(2004)},
+		"Sony-Ericsson": {oem: "Sony", model: "Ericsson", bodyWeight: Some[float64](100), launch: announcedIn(2004)},
+		"Nokia-N95":     {oem: "Nokia", model: "N95", bodyWeight: Some[float64](150), launch: announcedIn(2007)},
+		"Nokia-E90":     {oem: "Nokia", model: "E90", bodyWeight: Some[float64](100), launch: announcedIn(2007)},
+		"Alcatel-OT":    {oem: "Alcatel", model: "OT", bodyWeight: Some[float64](125), launch: announcedIn(2003)},
+	}}
+
+	// map iteration order changes from run to run, so look more than once
+	for i := 0; i < 20; i++ {
+		extremes := cells.FindHeaviestAndLightestPhones().Value
+		if extremes.Heaviest.model != "N95" || extremes.Lightest.model != "E90" {
+			t.Fatalf("cells.FindHeaviestAndLightestPhones() = %s, %s; want N95, E90", extremes.Heaviest.model, extremes.Lightest.model)
+		}
+		if got := cells.FindOEMWithHighestAverageWeight(); got != "Alcatel" {
+			t.Fatalf("cells.FindOEMWithHighestAverageWeight() = %s; want Alcatel", got)
+		}
+		latest := cells.FindLatestPhoneByOEM()
+		if latest["Nokia"].model != "E90" || latest["Sony"].model != "Ericsson" {
+			t.Fatalf("cells.FindLatestPhoneByOEM() = %s, %s; want E90, Ericsson", latest["Nokia"].model, latest["Sony"].model)
+		}
+		counts := cells.CountPhonesByYear().Value
+		if got := FindMostLaunchesIn2000s(counts); got != 2004 {
+			t.Fatalf("FindMostLaunchesIn2000s() = %d; want 2004", got)
+		}
+	}
+}
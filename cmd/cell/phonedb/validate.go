@@ -0,0 +1,346 @@
+package phonedb
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// maxExamples is the number of distinct raw values kept for each column
+// that failed to parse.
+const maxExamples = 3
+
+// columnParsers report whether a non-empty raw value of a column parses.
+// The oem and model columns are free text and always parse.
+var columnParsers = map[string]func(string) bool{
+	ColumnOEM:               func(string) bool { return true },
+	ColumnModel:             func(string) bool { return true },
+	ColumnLaunchAnnounced:   func(s string) bool { return ParseDate(s) != nil },
+	ColumnLaunchStatus:      func(s string) bool { return ParseStatus(s) != StatusUnknown },
+	ColumnBodyDimensions:    func(s string) bool { return ParseDimensions(s) != nil },
+	ColumnBodyWeight:        func(s string) bool { return ParseWeight(s) != nil },
+	ColumnBodySim:           func(s string) bool { return ParseSim(s) != nil },
+	ColumnDisplayType:       func(s string) bool { return ParseDisplayType(s) != nil },
+	ColumnDisplaySize:       func(s string) bool { return ParseSize(s) != nil },
+	ColumnDisplayResolution: func(s string) bool { return ParseResolution(s) != nil },
+	ColumnFeaturesSensors:   func(s string) bool { return ParseSensors(s) != nil },
+	ColumnPlatformOS:        func(s string) bool { return ParsePlatformOS(s) != nil },
+}
+
+// Range is the span of plausible values for a numeric column.
+type Range struct {
+	Column string
+	Min    float64
+	Max    float64
+	// unit the values are written in, such as "g"
+	Unit string
+}
+
+// DefaultRanges are the plausible values of the numeric columns: phones
+// weigh at most a kilogram, have displays of at most 15 inches and were
+// announced from 1990 onward.
+var DefaultRanges = []Range{
+	{Column: ColumnBodyWeight, Min: 1, Max: 1000, Unit: "g"},
+	{Column: ColumnDisplaySize, Min: 0.5, Max: 15, Unit: "in"},
+	{Column: ColumnLaunchAnnounced, Min: 1990, Max: math.Inf(1)},
+}
+
+// rangeValues return the parsed numeric value of a column, if it parses.
+var rangeValues = map[string]func(string) (float64, bool){
+	ColumnBodyWeight: func(s string) (float64, bool) {
+		if w := ParseWeight(s); w != nil {
+			return *w, true
+		}
+		return 0, false
+	},
+	ColumnDisplaySize: func(s string) (float64, bool) {
+		if size := ParseSize(s); size != nil {
+			return *size, true
+		}
+		return 0, false
+	},
+	ColumnLaunchAnnounced: func(s string) (float64, bool) {
+		if d := ParseDate(s); d != nil {
+			return float64(d.Year), true
+		}
+		return 0, false
+	},
+}
+
+// ColumnQuality summarizes how well one column of cells.csv is filled in.
+type ColumnQuality struct {
+	Column string
+	// number of rows checked
+	Rows int
+	// number of rows with a value other than "" or "-"
+	Filled int
+	// number of filled rows whose value did not parse
+	Failed int
+	// up to maxExamples distinct raw values that did not parse
+	Examples []string
+}
+
+// FillRate returns the fraction of rows with a value, or 0 if there are no
+// rows.
+func (q ColumnQuality) FillRate() float64 {
+	if q.Rows == 0 {
+		return 0
+	}
+	return float64(q.Filled) / float64(q.Rows)
+}
+
+// FailureRate returns the fraction of filled rows whose value did not parse,
+// or 0 if no row is filled.
+func (q ColumnQuality) FailureRate() float64 {
+	if q.Filled == 0 {
+		return 0
+	}
+	return float64(q.Failed) / float64(q.Filled)
+}
+
+// OutOfRange describes a value outside the plausible Range of its column.
+type OutOfRange struct {
+	// line number of the row in the file, counting the header as line 1
+	Line  int
+	OEM   string
+	Model string
+	Range Range
+	// raw value as it appears in the file
+	Raw string
+	// parsed value
+	Value float64
+}
+
+// String implements the Stringer interface for OutOfRange.
+func (o OutOfRange) String() string {
+	limits := fmt.Sprintf("at least %g", o.Range.Min)
+	if !math.IsInf(o.Range.Max, 1) {
+		limits = fmt.Sprintf("between %g and %g", o.Range.Min, o.Range.Max)
+	}
+	value := fmt.Sprintf("%g", o.Value)
+	if o.Range.Unit != "" {
+		value += " " + o.Range.Unit
+	}
+	return fmt.Sprintf("line %d: %s %s: %s %s, want %s", o.Line, o.OEM, o.Model, o.Range.Column, value, limits)
+}
+
+// QualityReport is the data-quality report of a cells.csv file.
+type QualityReport struct {
+	// number of data rows, not counting the header
+	Rows int
+	// rows the csv reader could not split, or whose fields could not be
+	// realigned with the header
+	Malformed []*RowError
+	// rows Load repairs by clearing placeholders or moving shifted values
+	// back into their columns, in file order
+	Repaired []*RowRepair
+	// rows Load holds back because their values are in the wrong columns,
+	// in file order; their errors wrap ErrShiftedRow
+	Quarantined []*RowError
+	// quality of every required column, in RequiredColumns order
+	Columns []ColumnQuality
+	// values outside DefaultRanges, in file order
+	OutOfRange []OutOfRange
+}
+
+// Column returns the quality of the named column, and false if the report
+// does not cover it.
+func (r *QualityReport) Column(name string) (ColumnQuality, bool) {
+	for _, q := range r.Columns {
+		if q.Column == name {
+			return q, true
+		}
+	}
+	return ColumnQuality{}, false
+}
+
+// Validate reads cells.csv formatted data from r and runs every column
+// through its parser, reporting how many values are filled in, which fail
+// to parse and which are outside DefaultRanges. Rows are checked with
+// Header.CheckRecord, as Load checks them, and listed as repaired or
+// quarantined in the report. Unlike Load it looks at the raw values, so
+// placeholders count as filled values that fail to parse.
+func Validate(r io.Reader) (*QualityReport, error) {
+	// rows with more or fewer fields than the header are left to checkRow,
+	// as in Load
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	names, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("read header: input is empty")
+		}
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	header, err := NewHeader(names)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &QualityReport{Columns: make([]ColumnQuality, len(RequiredColumns))}
+	for i, name := range RequiredColumns {
+		report.Columns[i].Column = name
+	}
+
+	for {
+		line, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report.Rows++
+			report.Malformed = append(report.Malformed, &RowError{Line: parseErr.StartLine, Record: line, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		report.Rows++
+		lineNum, _ := reader.FieldPos(0)
+		check, rowErr := header.checkRow(line, lineNum)
+		if rowErr != nil {
+			report.Malformed = append(report.Malformed, rowErr)
+			continue
+		}
+		if check.Err != nil {
+			report.Quarantined = append(report.Quarantined, &RowError{Line: lineNum, Record: line, Err: check.Err})
+		} else if check.Repaired() {
+			report.Repaired = append(report.Repaired, &RowRepair{Line: lineNum, Placeholders: check.Placeholders, Shift: check.Shift})
+		}
+
+		for i, name := range RequiredColumns {
+			q := &report.Columns[i]
+			q.Rows++
+			raw := strings.TrimSpace(header.Value(line, name))
+			if raw == "" || raw == "-" {
+				continue
+			}
+			q.Filled++
+			if !columnParsers[name](raw) {
+				q.Failed++
+				q.Examples = addExample(q.Examples, raw)
+			}
+		}
+
+		for _, rng := range DefaultRanges {
+			raw := header.Value(line, rng.Column)
+			value, ok := rangeValues[rng.Column](raw)
+			if ok && (value < rng.Min || value > rng.Max) {
+				report.OutOfRange = append(report.OutOfRange, OutOfRange{
+					Line:  lineNum,
+					OEM:   header.Value(line, ColumnOEM),
+					Model: header.Value(line, ColumnModel),
+					Range: rng,
+					Raw:   raw,
+					Value: value,
+				})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// addExample appends raw to examples unless it is already listed or there
+// are maxExamples of them.
+func addExample(examples []string, raw string) []string {
+	if len(examples) >= maxExamples {
+		return examples
+	}
+	for _, example := range examples {
+		if example == raw {
+			return examples
+		}
+	}
+	return append(examples, raw)
+}
+
+// Thresholds are the limits a QualityReport must stay within. A column
+// missing from a map is not checked; the key "*" applies to every column
+// not listed by name.
+type Thresholds struct {
+	// lowest acceptable fill rate of each column, between 0 and 1
+	MinFillRate map[string]float64 `json:"min_fill_rate"`
+	// highest acceptable parse failure rate of each column, between 0 and 1
+	MaxFailureRate map[string]float64 `json:"max_failure_rate"`
+	// highest acceptable number of out of range values, unchecked if nil
+	MaxOutOfRange *int `json:"max_out_of_range"`
+	// highest acceptable number of malformed rows, unchecked if nil
+	MaxMalformed *int `json:"max_malformed"`
+}
+
+// ReadThresholds decodes Thresholds from JSON such as
+//
+//	{"min_fill_rate": {"oem": 1}, "max_failure_rate": {"*": 0.1}, "max_out_of_range": 0}
+//
+// Unknown keys and column names are rejected so a typo does not silently
+// disable a check.
+func ReadThresholds(r io.Reader) (*Thresholds, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	var t Thresholds
+	if err := decoder.Decode(&t); err != nil {
+		return nil, fmt.Errorf("read thresholds: %w", err)
+	}
+	for _, limits := range []map[string]float64{t.MinFillRate, t.MaxFailureRate} {
+		for name := range limits {
+			if _, ok := columnParsers[name]; !ok && name != "*" {
+				return nil, fmt.Errorf("read thresholds: unknown column %q", name)
+			}
+		}
+	}
+	return &t, nil
+}
+
+// limit returns the limit for the named column, falling back to "*".
+func limit(limits map[string]float64, name string) (float64, bool) {
+	if l, ok := limits[name]; ok {
+		return l, true
+	}
+	l, ok := limits["*"]
+	return l, ok
+}
+
+// ThresholdViolation describes a measure of a QualityReport beyond its limit.
+type ThresholdViolation struct {
+	// column the measure is of, empty for measures of the whole file
+	Column string `json:"column,omitempty"`
+	// name of the measure, such as "fill rate"
+	Measure string  `json:"measure"`
+	Value   float64 `json:"value"`
+	Limit   float64 `json:"limit"`
+}
+
+// String implements the Stringer interface for ThresholdViolation.
+func (v ThresholdViolation) String() string {
+	if v.Column == "" {
+		return fmt.Sprintf("%s is %g, limit %g", v.Measure, v.Value, v.Limit)
+	}
+	return fmt.Sprintf("%s %s is %.3f, limit %.3f", v.Column, v.Measure, v.Value, v.Limit)
+}
+
+// Check compares the report against t and returns every violated limit,
+// columns first in RequiredColumns order.
+func (r *QualityReport) Check(t *Thresholds) []ThresholdViolation {
+	var violations []ThresholdViolation
+	for _, q := range r.Columns {
+		if lowest, ok := limit(t.MinFillRate, q.Column); ok && q.FillRate() < lowest {
+			violations = append(violations, ThresholdViolation{Column: q.Column, Measure: "fill rate", Value: q.FillRate(), Limit: lowest})
+		}
+		if highest, ok := limit(t.MaxFailureRate, q.Column); ok && q.FailureRate() > highest {
+			violations = append(violations, ThresholdViolation{Column: q.Column, Measure: "failure rate", Value: q.FailureRate(), Limit: highest})
+		}
+	}
+	if t.MaxOutOfRange != nil && len(r.OutOfRange) > *t.MaxOutOfRange {
+		violations = append(violations, ThresholdViolation{Measure: "out of range values", Value: float64(len(r.OutOfRange)), Limit: float64(*t.MaxOutOfRange)})
+	}
+	if t.MaxMalformed != nil && len(r.Malformed) > *t.MaxMalformed {
+		violations = append(violations, ThresholdViolation{Measure: "malformed rows", Value: float64(len(r.Malformed)), Limit: float64(*t.MaxMalformed)})
+	}
+	return violations
+}
@@ -0,0 +1,140 @@
+package phonedb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	data := strings.Join(RequiredColumns, ",") + "\n" +
+		"Google,Pixel 4,2019,Available,147.1 x 68.8 x 8.2 mm,162 g,Nano-SIM,OLED,5.7 inches,1080 x 2280 pixels,Accelerometer,Android 10\n" +
+		"Motorola,XOOM,2011,Discontinued,-,1730 g,No,TFT,10.1 inches,-,V1,\n" +
+		"Benefon,Vega,1989,Discontinued,V1,190 g,Mini-SIM,Monochrome,,6 lines,V1,\n"
+
+	report, err := Validate(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if report.Rows != 3 {
+		t.Errorf("Validate().Rows = %d; want 3", report.Rows)
+	}
+
+	tests := []struct {
+		column       string
+		wantFilled   int
+		wantFailed   int
+		wantExamples []string
+	}{
+		{ColumnOEM, 3, 0, nil},
+		{ColumnBodyDimensions, 2, 1, []string{"V1"}},
+		{ColumnBodySim, 3, 1, []string{"No"}},
+		{ColumnDisplaySize, 2, 0, nil},
+		{ColumnFeaturesSensors, 3, 2, []string{"V1"}},
+		{ColumnPlatformOS, 1, 0, nil},
+	}
+	for _, tt := range tests {
+		q, ok := report.Column(tt.column)
+		if !ok {
+			t.Errorf("Validate().Column(%q) not found", tt.column)
+			continue
+		}
+		if q.Rows != 3 || q.Filled != tt.wantFilled || q.Failed != tt.wantFailed || !reflect.DeepEqual(q.Examples, tt.wantExamples) {
+			t.Errorf("Validate().Column(%q) = %+v; want 3 rows, %d filled, %d failed, examples %q",
+				tt.column, q, tt.wantFilled, tt.wantFailed, tt.wantExamples)
+		}
+	}
+
+	var got []string
+	for _, o := range report.OutOfRange {
+		got = append(got, o.String())
+	}
+	want := []string{
+		"line 3: Motorola XOOM: body_weight 1730 g, want between 1 and 1000",
+		"line 4: Benefon Vega: launch_announced 1989, want at least 1990",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate().OutOfRange = %q; want %q", got, want)
+	}
+}
+
+// TestValidateAgreesWithLoad checks that Validate reports the rows Load
+// rejects, repairs and quarantines.
+func TestValidateAgreesWithLoad(t *testing.T) {
+	data := strings.Join(RequiredColumns, ",") + "\n" +
+		"Google,Pixel 4,2019,Available,147.1 x 68.8 x 8.2 mm,162 g,Nano-SIM,OLED,5.7 inches,1080 x 2280 pixels,Accelerometer,Android 10\n" +
+		// one field too many, realigned by moving the values left
+		"Google,Pixel 4 XL,2019,Available,160.4 x 75.1 x 8.2 mm,(6.81 oz),193 g,Nano-SIM,OLED,6.3 inches,1440 x 3040 pixels,Accelerometer,Android 10\n" +
+		// one field short, realigned by moving the values right
+		"Samsung,Galaxy S10,2019,Available,157 g,Nano-SIM,AMOLED,6.1 inches,1440 x 3040 pixels,Accelerometer,Android 9\n" +
+		// values in the wrong columns that cannot be realigned
+		"Google,Pixel 3,2018,Available,Android 9,148 g,Nano-SIM,5.5 inches,OLED,1080 x 2160 pixels,Accelerometer,\n" +
+		// too short to be a shifted row
+		"Google,Pixel 2,2017\n"
+
+	quality, err := Validate(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	_, report, err := Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if quality.Rows != 5 || report.Rows != 5 {
+		t.Errorf("Validate().Rows = %d, LoadReport.Rows = %d; want 5", quality.Rows, report.Rows)
+	}
+	if len(quality.Malformed) != 1 || quality.Malformed[0].Line != 6 || !reflect.DeepEqual(quality.Malformed, report.Errors) {
+		t.Errorf("Validate().Malformed = %v; want line 6, as LoadReport.Errors = %v", quality.Malformed, report.Errors)
+	}
+	if len(quality.Repaired) != 2 || !reflect.DeepEqual(quality.Repaired, report.Repaired) {
+		t.Errorf("Validate().Repaired = %v; want 2 rows, as LoadReport.Repaired = %v", quality.Repaired, report.Repaired)
+	}
+	if len(quality.Quarantined) != 1 || quality.Quarantined[0].Line != 5 || !reflect.DeepEqual(quality.Quarantined, report.Quarantined) {
+		t.Errorf("Validate().Quarantined = %v; want line 5, as LoadReport.Quarantined = %v", quality.Quarantined, report.Quarantined)
+	}
+}
+
+func TestCheckThresholds(t *testing.T) {
+	thresholds, err := ReadThresholds(strings.NewReader(`{
+		"min_fill_rate": {"oem": 1, "*": 0.5},
+		"max_failure_rate": {"*": 0.1},
+		"max_out_of_range": 0
+	}`))
+	if err != nil {
+		t.Fatalf("ReadThresholds() error = %v", err)
+	}
+
+	report := &QualityReport{
+		Columns: []ColumnQuality{
+			{Column: ColumnOEM, Rows: 10, Filled: 10},
+			{Column: ColumnDisplaySize, Rows: 10, Filled: 4},
+			{Column: ColumnFeaturesSensors, Rows: 10, Filled: 10, Failed: 5},
+		},
+		OutOfRange: []OutOfRange{{Line: 2}},
+	}
+
+	var got []string
+	for _, v := range report.Check(thresholds) {
+		got = append(got, v.String())
+	}
+	want := []string{
+		"display_size fill rate is 0.400, limit 0.500",
+		"features_sensors failure rate is 0.500, limit 0.100",
+		"out of range values is 1, limit 0",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("report.Check() = %q; want %q", got, want)
+	}
+}
+
+func TestReadThresholdsRejectsUnknownNames(t *testing.T) {
+	for _, input := range []string{
+		`{"min_fill_rate": {"weight": 1}}`,
+		`{"min_fil_rate": {"oem": 1}}`,
+	} {
+		if _, err := ReadThresholds(strings.NewReader(input)); err == nil {
+			t.Errorf("ReadThresholds(%s) error = nil; want an error", input)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"cell/chart"
+	"cell/phonedb"
+)
+
+// reportTemplate is the HTML page "cell report" fills in. Everything the
+// page needs, its style, script and charts, ends up inside the one file.
+//
+//go:embed report.html
+var reportTemplate string
+
+// reportCharts are the charts of a report, as they would be given to
+// "cell chart".
+var reportCharts = []struct {
+	name, field string
+	top         int
+}{
+	{name: "launches"},
+	{name: "oems", top: 10},
+	{name: "share", top: 6},
+	{name: "histogram", field: "weight"},
+	{name: "trend", field: "weight"},
+	{name: "scatter", field: "size"},
+}
+
+// report is what the report template is filled in with.
+type report struct {
+	Source string
+	// the -where expression, empty if every phone is reported
+	Where   string
+	Summary phonedb.Summary
+	// the reportCharts as inline SVG
+	Charts []template.HTML
+	// quality of the whole file, regardless of -where
+	Quality *phonedb.QualityReport
+}
+
+// runReport implements "cell report", which writes the statistics, charts
+// and data quality of the phones as a single HTML file.
+func runReport(args []string) int {
+	flags := newFlagSet("report", "cell report [-data file] [-where expr] [-sort name|count|latest] -html file",
+		"Report writes the statistics printed by \"cell stats\", the charts drawn by\n"+
+			"\"cell chart\" and the data quality reported by \"cell validate\" to one\n"+
+			"self-contained HTML file, whose tables sort by any column when its\n"+
+			"heading is clicked. -where selects the phones of the statistics and\n"+
+			"charts; the data quality always covers the whole file.")
+	data := dataFlag(flags)
+	where := whereFlag(flags)
+	order := sortFlag(flags, "OEM table when the page opens: name, count or latest")
+	output := flags.String("html", "", "write the report to `file`")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() > 0 || *output == "" {
+		flags.Usage()
+		return 2
+	}
+
+	cells, err := loadCatalog(*data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	cells = cells.Where(where)
+
+	raw, err := os.ReadFile(*data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	quality, err := phonedb.Validate(bytes.NewReader(raw))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+
+	r := report{Source: *data, Where: where.String(), Summary: cells.Summary(), Quality: quality}
+	phonedb.SortOEMs(r.Summary.OEMs, *order)
+	if r.Charts, err = renderCharts(cells); err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+
+	page, err := template.New("report").Funcs(template.FuncMap{
+		"stat":    formatStat,
+		"percent": func(rate float64) string { return fmt.Sprintf("%.1f%%", 100*rate) },
+		"limits":  limits,
+		"stats":   distributionStats,
+	}).Parse(reportTemplate)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	if err := writeOutput(*output, func(w io.Writer) error { return page.Execute(w, r) }); err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	return 0
+}
+
+// renderCharts draws the reportCharts of cells as SVG, ready to be placed
+// in the page as they are.
+func renderCharts(cells *phonedb.Catalog) ([]template.HTML, error) {
+	charts := make([]template.HTML, len(reportCharts))
+	for i, spec := range reportCharts {
+		var metric phonedb.Metric
+		if spec.field != "" {
+			var err error
+			if metric, err = phonedb.FieldMetric(spec.field); err != nil {
+				return nil, err
+			}
+		}
+		var svg strings.Builder
+		if err := newChart(cells, spec.name, spec.field, metric, 20, spec.top).writeSVG(&svg); err != nil {
+			return nil, err
+		}
+		// the chart package escapes every label it draws
+		charts[i] = template.HTML(svg.String())
+	}
+	return charts, nil
+}
+
+// limits describes the plausible values of a column, as in "1 to 1000 g"
+// or "at least 1990".
+func limits(r phonedb.Range) string {
+	s := "at least " + chart.FormatValue(r.Min)
+	if !math.IsInf(r.Max, 1) {
+		s = chart.FormatValue(r.Min) + " to " + chart.FormatValue(r.Max)
+	}
+	if r.Unit != "" {
+		s += " " + r.Unit
+	}
+	return s
+}
+
+// distributionStats returns the statistics of d in the columns of the
+// distribution table: mean, standard deviation, minimum, 5th, 25th, 50th,
+// 75th and 95th percentile, maximum and interquartile range.
+func distributionStats(d phonedb.Distribution) []float64 {
+	return []float64{d.Mean, d.StdDev, d.Min, d.P5, d.P25, d.Median, d.P75, d.P95, d.Max, d.IQR}
+}
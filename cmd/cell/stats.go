@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"cell/chart"
+	"cell/phonedb"
+)
+
+// runStats implements "cell stats", which prints statistics about the whole
+// collection of phones.
+func runStats(args []string) int {
+	flags := newFlagSet("stats", "cell stats [-data file] [-format text|json] [-sort name|count|latest] [-where expr]",
+		"Stats prints averages, counts and rankings over every phone in the data.\n"+
+			"With -format json it writes them as a versioned JSON document instead.")
+	data := dataFlag(flags)
+	format := formatFlag(flags)
+	order := sortFlag(flags, "OEM table: name, count (most phones first) or\nlatest (most recent latest model first); ties are broken by name")
+	where := whereFlag(flags)
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	cells, err := loadCatalog(*data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	cells = cells.Where(where)
+
+	summary := cells.Summary()
+	phonedb.SortOEMs(summary.OEMs, *order)
+
+	if *format == "json" {
+		if err := writeJSON(os.Stdout, phonedb.NewDocument("stats", *data, summary)); err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 1
+		}
+		return 0
+	}
+
+	// Calculating and displaying the statistics for the cell phone collection
+	fmt.Println("Collection Statistics:")
+	// each statistic notes how many phones were left out for missing data
+	weight := cells.AverageWeight()
+	fmt.Printf("Average cell weight: %.2f g (%d phones without a weight excluded)\n", weight.Value, weight.Excluded)
+	size := cells.AverageDisplaySize()
+	fmt.Printf("Average cell size: %.2f in (%d phones without a size excluded)\n", size.Value, size.Excluded)
+	thickness := cells.AverageThickness()
+	fmt.Printf("Average cell thickness: %.2f mm (%d phones without a thickness excluded)\n", thickness.Value, thickness.Excluded)
+	uniqueOS := cells.CountUniqueOS()
+	fmt.Printf("Number of Unique Operating Systems: %d (%d phones without an OS excluded)\n", uniqueOS.Value, uniqueOS.Excluded)
+	fmt.Printf("Number of Unique Operating System Families: %d\n", cells.CountUniqueOSFamilies().Value)
+	oneSensor := cells.CountPhonesWithOneSensor()
+	fmt.Printf("There are %d phones with only one feature sensor (%d phones without sensors excluded).\n", oneSensor.Value, oneSensor.Excluded)
+
+	// Finding and printing the heaviest and lightest phones
+	extremes := cells.FindHeaviestAndLightestPhones().Value
+	if heaviest, lightest := extremes.Heaviest, extremes.Lightest; heaviest != nil {
+		fmt.Printf("Heaviest Phone: %s\n", heaviest.OEM()+"'s "+heaviest.Model()+", "+fmt.Sprintf("%.2f", heaviest.BodyWeight().Value())+" g")
+		fmt.Printf("Lightest Phone: %s\n", lightest.OEM()+"'s "+lightest.Model()+", "+fmt.Sprintf("%.2f", lightest.BodyWeight().Value())+" g")
+	}
+
+	// Find the OEM with the highest average weight, and print the result
+	fmt.Println("The OEM with the highest average phone body weight is:", cells.FindOEMWithHighestAverageWeight())
+	fmt.Println()
+
+	// Means are skewed by tablets and rugged phones, so print the spread of
+	// every numeric field as well
+	fmt.Println("Distribution of each numeric field:")
+	fmt.Printf("%-17s %5s %10s %10s %10s %10s %10s %10s %10s %10s\n", "Field", "Known", "Mean", "StdDev", "P5", "P25", "Median", "P75", "P95", "IQR")
+	for _, field := range summary.Distributions {
+		d := field.Distribution.Value
+		if d.Count == 0 {
+			fmt.Printf("%-17s %5d\n", field.Field, 0)
+			continue
+		}
+		fmt.Printf("%-17s %5d", field.Field, d.Count)
+		for _, v := range []float64{d.Mean, d.StdDev, d.P5, d.P25, d.Median, d.P75, d.P95, d.IQR} {
+			fmt.Printf(" %10s", formatStat(v))
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+
+	// Counting phones released each year and printing the result
+	fmt.Println("Number of cell announcements by year:")
+	byYear := cells.CountPhonesByYear()
+	counts := byYear.Value
+	if err := chart.WriteBars(os.Stdout, yearBars(counts.Counts), terminalWidth()); err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+	fmt.Printf("%d phones without an announcement year were not counted.\n", byYear.Excluded)
+	fmt.Printf("The year with the most phone launches in the 2000s was %d.\n", phonedb.FindMostLaunchesIn2000s(counts))
+	if year, ok := cells.FindDualSimOvertakeYear(); ok {
+		fmt.Printf("Dual SIM phones first outnumbered single SIM phones in %d.\n", year)
+	}
+	fmt.Println()
+
+	// Counting phones by OEM and finding the latest phone model for each OEM
+	fmt.Println("Count of phones and the latest model by each OEM:")
+	fmt.Printf("%-15s %-10s %-25s %s\n", "OEM", "Count", "Latest Model", "Year")
+	for _, oem := range summary.OEMs {
+		year := "-"
+		if oem.LatestYear > 0 {
+			year = fmt.Sprint(oem.LatestYear)
+		}
+		fmt.Printf("%-15s %-10d %-25s %s\n", oem.OEM, oem.Phones, oem.LatestModel, year)
+	}
+	fmt.Println()
+
+	// Summarizing how long each OEM takes to release a phone after announcing it,
+	// fastest first
+	fmt.Println("Average and median days from announcement to release by OEM:")
+	fmt.Printf("%-15s %-10s %-10s %-10s\n", "OEM", "Phones", "Mean", "Median")
+	lags := cells.LagByOEM().Value
+	lagOEMs := make([]string, 0, len(lags))
+	for oem := range lags {
+		lagOEMs = append(lagOEMs, oem)
+	}
+	sort.Slice(lagOEMs, func(i, j int) bool {
+		a, b := lags[lagOEMs[i]], lags[lagOEMs[j]]
+		if a.Mean != b.Mean {
+			return a.Mean < b.Mean
+		}
+		return lagOEMs[i] < lagOEMs[j]
+	})
+	for _, oem := range lagOEMs {
+		fmt.Printf("%-15s %-10d %-10.1f %-10.1f\n", oem, lags[oem].Count, lags[oem].Mean, lags[oem].Median)
+	}
+	fmt.Println()
+
+	// The same lag by the year the phones were announced in, to show whether
+	// phones reach the shelves sooner than they used to
+	fmt.Println("Average and median days from announcement to release by year announced:")
+	fmt.Printf("%-15s %-10s %-10s %-10s\n", "Year", "Phones", "Mean", "Median")
+	for _, year := range summary.LagByYear.Value {
+		fmt.Printf("%-15d %-10d %-10.1f %-10.1f\n", year.Year, year.Lag.Count, year.Lag.Mean, year.Lag.Median)
+	}
+	fmt.Printf("%d phones without both an announcement and a release date were not counted.\n", summary.LagByYear.Excluded)
+	fmt.Println()
+
+	// Find the phones that were announced and released in different years
+	phones := cells.FindPhonesAnnouncedAndReleasedDifferentYears().Value
+	// Print the result
+	if len(phones) > 0 {
+		fmt.Println("The following phones were announced and released in different years:")
+		for _, phone := range phones {
+			fmt.Printf("OEM: %s, Model: %s\n", phone.OEM, phone.Model)
+		}
+	} else {
+		fmt.Println("No phones were announced and released in different years.")
+	}
+	return 0
+}
+
+// formatStat formats a statistic with two decimals, or with four
+// significant digits if that would not fit in a column of ten characters,
+// as for display colors.
+func formatStat(v float64) string {
+	if s := strconv.FormatFloat(v, 'f', 2, 64); len(s) <= 10 {
+		return s
+	}
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package main
+
+import "os"
+
+// terminalColumns returns 0, as the size of a terminal is only known on
+// Linux and macOS.
+func terminalColumns(f *os.File) int {
+	return 0
+}
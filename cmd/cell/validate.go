@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"cell/phonedb"
+)
+
+// runValidate implements "cell validate", which prints a data-quality report
+// of a cells.csv file, given as an argument or by -data, and the phones that
+// break any rule. It returns 1 if the file cannot be read, a threshold is
+// violated or a rule of error severity is broken, so it can gate a data
+// pipeline.
+func runValidate(args []string) int {
+	flags := newFlagSet("validate", "cell validate [-data file] [-format text|json] [-thresholds file] [-rules file] [file]",
+		"Validate reports how many values of each column are filled in and parse,\n"+
+			"the values outside their plausible range and, with -rules, the phones\n"+
+			"breaking a rule. It exits with status 1 if a threshold is violated or a\n"+
+			"rule of error severity is broken. A file given as an argument is read\n"+
+			"instead of the one given by -data.")
+	dataPath := dataFlag(flags)
+	format := formatFlag(flags)
+	thresholdsPath := flags.String("thresholds", "", "JSON `file` of limits the data must stay within")
+	rulesPath := flags.String("rules", "", "JSON `file` of rules every phone must meet")
+	if status, ok := parseFlags(flags, args); !ok {
+		return status
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return 2
+	}
+	path := *dataPath
+	if flags.NArg() == 1 {
+		path = flags.Arg(0)
+	}
+
+	// read the thresholds and rules first so a bad file is reported before
+	// the data
+	var thresholds *phonedb.Thresholds
+	if *thresholdsPath != "" {
+		file, err := os.Open(*thresholdsPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 1
+		}
+		thresholds, err = phonedb.ReadThresholds(file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 1
+		}
+	}
+	var rules *phonedb.RuleSet
+	if *rulesPath != "" {
+		file, err := os.Open(*rulesPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 1
+		}
+		rules, err = phonedb.ReadRules(file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 1
+		}
+	}
+
+	// the data is read twice, raw for the report and parsed for the rules
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+
+	report, err := phonedb.Validate(bytes.NewReader(data))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cell:", err)
+		return 1
+	}
+
+	// run the checks before printing anything, so both formats report the
+	// same outcome
+	var ruleViolations []phonedb.RuleViolation
+	if rules != nil {
+		// keep every row so each rule violation points at its own line
+		cells, _, err := phonedb.Loader{Duplicates: phonedb.KeepBoth}.Load(bytes.NewReader(data))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 1
+		}
+		ruleViolations = rules.Evaluate(cells)
+	}
+	var thresholdViolations []phonedb.ThresholdViolation
+	if thresholds != nil {
+		thresholdViolations = report.Check(thresholds)
+	}
+
+	status := 0
+	for _, v := range ruleViolations {
+		if v.Rule.Severity == phonedb.SeverityError {
+			status = 1
+		}
+	}
+	if len(thresholdViolations) > 0 {
+		status = 1
+	}
+
+	if *format == "json" {
+		doc := report.Document()
+		if thresholds != nil {
+			doc.ThresholdViolations = append([]phonedb.ThresholdViolation{}, thresholdViolations...)
+		}
+		if rules != nil {
+			doc.RuleViolations = make([]phonedb.RuleViolationDocument, len(ruleViolations))
+			for i, v := range ruleViolations {
+				doc.RuleViolations[i] = v.Document()
+			}
+		}
+		if err := writeJSON(os.Stdout, phonedb.NewDocument("quality", path, doc)); err != nil {
+			fmt.Fprintln(os.Stderr, "cell:", err)
+			return 1
+		}
+		return status
+	}
+
+	fmt.Printf("Data quality of %s (%d rows, %d malformed, %d repaired, %d quarantined):\n",
+		path, report.Rows, len(report.Malformed), len(report.Repaired), len(report.Quarantined))
+	fmt.Printf("%-20s %-8s %-8s %s\n", "Column", "Filled", "Failed", "Examples of failures")
+	for _, q := range report.Columns {
+		examples := make([]string, len(q.Examples))
+		for i, example := range q.Examples {
+			examples[i] = strconv.Quote(example)
+		}
+		fmt.Printf("%-20s %6.1f%%  %6.1f%%  %s\n", q.Column, 100*q.FillRate(), 100*q.FailureRate(), strings.Join(examples, ", "))
+	}
+	for _, rowErr := range report.Malformed {
+		fmt.Println("malformed", rowErr)
+	}
+	for _, rowErr := range report.Quarantined {
+		fmt.Println("quarantined", rowErr)
+	}
+	fmt.Printf("\n%d values out of range:\n", len(report.OutOfRange))
+	for _, o := range report.OutOfRange {
+		fmt.Println(o)
+	}
+	if rules != nil {
+		fmt.Printf("\n%d rule violations:\n", len(ruleViolations))
+		for _, v := range ruleViolations {
+			fmt.Println(v)
+		}
+	}
+	for _, v := range thresholdViolations {
+		fmt.Fprintln(os.Stderr, "cell: threshold violated:", v)
+	}
+	return status
+}
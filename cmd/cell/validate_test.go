@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cell/phonedb"
+)
+
+func TestValidateFileArgument(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cells.csv")
+	data := strings.Join(phonedb.RequiredColumns, ",") + "\n" +
+		"Google,Pixel 4 XL,2019,Available,-,193 g,Nano-SIM,OLED,6.3 inches,-,Accelerometer,Android 10\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.csv")
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"validate", path}, 0},
+		{[]string{"validate", "-format", "json", path}, 0},
+		// the argument is read instead of -data
+		{[]string{"validate", "-data", missing, path}, 0},
+		{[]string{"validate", "-data", path}, 0},
+		{[]string{"validate", missing}, 1},
+		{[]string{"validate", path, path}, 2},
+	}
+	for _, tt := range tests {
+		if got := run(tt.args); got != tt.want {
+			t.Errorf("run(%q) = %d; want %d", tt.args, got, tt.want)
+		}
+	}
+}